@@ -16,6 +16,7 @@ type BaseCmdForCtree struct {
 	Generate *cobra.Command
 	Get      *cobra.Command
 	List     *cobra.Command
+	Path     *cobra.Command
 	Version  *cobra.Command
 }
 
@@ -34,12 +35,14 @@ Examples:
   ctree generate cpp --source ./myapp           # generate for C++ project
   ctree generate rust --source ./myapp          # generate for Rust project
   ctree get golang functions                    # get function information
-  ctree list golang --type functions            # list all functions`,
+  ctree list golang --type functions            # list all functions
+  ctree path --ctree tree.yaml --from main --to os.Exit  # find call paths`,
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
 			// TODO: Set output format if needed
 		},
 	}
-	rootCmd.PersistentFlags().StringVarP(&output, "output-format", "f", "yaml", "Output format: yaml|json|table")
+	// No shorthand: subcommands use -f for their own --format flag
+	rootCmd.PersistentFlags().StringVar(&output, "output-format", "yaml", "Output format: yaml|json|table")
 	return rootCmd
 }
 
@@ -81,6 +84,9 @@ func InitBaseCmdForCtree(conf *config.Config) BaseCmdForCtree {
 	// listCmd.AddCommand(rust_controller.InitListRustCmd(conf))    // TODO: Implement Rust support
 	listCmd.AddCommand(python_controller.InitListPythonCmd(conf))
 
+	// Create path command
+	pathCmd := golang_controller.InitPathCmd(conf)
+
 	// Create version command
 	versionCmd := &cobra.Command{
 		Use:   "version",
@@ -94,6 +100,7 @@ func InitBaseCmdForCtree(conf *config.Config) BaseCmdForCtree {
 		Generate: generateCmd,
 		Get:      getCmd,
 		List:     listCmd,
+		Path:     pathCmd,
 		Version:  versionCmd,
 	}
 }
@@ -109,6 +116,7 @@ func ClientForCtree(conf *config.Config) {
 	rootCmd.AddCommand(baseCmd.Generate)
 	rootCmd.AddCommand(baseCmd.Get)
 	rootCmd.AddCommand(baseCmd.List)
+	rootCmd.AddCommand(baseCmd.Path)
 	rootCmd.AddCommand(baseCmd.Version)
 
 	// Execute the root command
@@ -3,9 +3,11 @@ package golang
 import (
 	"fmt"
 	"os"
+	"runtime"
 
 	"github.com/ryo-arima/ctree/pkg/config"
 	"github.com/ryo-arima/ctree/pkg/entity/request"
+	golang_usecase "github.com/ryo-arima/ctree/pkg/usecase/golang"
 	"github.com/spf13/cobra"
 )
 
@@ -22,6 +24,15 @@ Supports various Go frameworks and pure projects.`,
 			recursive, _ := cmd.Flags().GetBool("recursive")
 			maxDepth, _ := cmd.Flags().GetInt("max-depth")
 			framework, _ := cmd.Flags().GetString("framework")
+			dispatch, _ := cmd.Flags().GetString("dispatch")
+			goos, _ := cmd.Flags().GetString("goos")
+			goarch, _ := cmd.Flags().GetString("goarch")
+			tags, _ := cmd.Flags().GetStringSlice("tags")
+			allPlatforms, _ := cmd.Flags().GetBool("all-platforms")
+			includeTests, _ := cmd.Flags().GetBool("include-tests")
+			entryPatterns, _ := cmd.Flags().GetStringSlice("entry")
+			exportedAsEntry, _ := cmd.Flags().GetBool("exported-as-entry")
+			followDeps, _ := cmd.Flags().GetInt("follow-deps")
 
 			if sourcePath == "" && len(args) > 0 {
 				sourcePath = args[0]
@@ -29,14 +40,27 @@ Supports various Go frameworks and pure projects.`,
 			if sourcePath == "" {
 				sourcePath = "."
 			}
+			if allPlatforms && (cmd.Flags().Changed("goos") || cmd.Flags().Changed("goarch") || cmd.Flags().Changed("tags")) {
+				fmt.Fprintln(os.Stderr, "Error: --all-platforms selects files for every platform and cannot be combined with --goos, --goarch or --tags")
+				return
+			}
 
 			req := request.GenerateRequest{
-				Language:   "golang",
-				Framework:  framework,
-				SourcePath: sourcePath,
-				OutputPath: outputPath,
-				Recursive:  recursive,
-				MaxDepth:   maxDepth,
+				Language:        "golang",
+				Framework:       framework,
+				SourcePath:      sourcePath,
+				OutputPath:      outputPath,
+				Recursive:       recursive,
+				MaxDepth:        maxDepth,
+				Dispatch:        dispatch,
+				GOOS:            goos,
+				GOARCH:          goarch,
+				Tags:            tags,
+				AllPlatforms:    allPlatforms,
+				IncludeTests:    includeTests,
+				EntryPatterns:   entryPatterns,
+				ExportedAsEntry: exportedAsEntry,
+				FollowDeps:      followDeps,
 			}
 
 			var result string
@@ -57,7 +81,7 @@ Supports various Go frameworks and pure projects.`,
 			}
 
 			if err != nil {
-				fmt.Printf("Error: %v\n", err)
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				return
 			}
 
@@ -65,10 +89,10 @@ Supports various Go frameworks and pure projects.`,
 			if outputPath != "" {
 				err = os.WriteFile(outputPath, []byte(result), 0644)
 				if err != nil {
-					fmt.Printf("Error writing to file %s: %v\n", outputPath, err)
+					fmt.Fprintf(os.Stderr, "Error writing to file %s: %v\n", outputPath, err)
 					return
 				}
-				fmt.Printf("Output written to %s\n", outputPath)
+				fmt.Fprintf(os.Stderr, "Output written to %s\n", outputPath)
 			} else {
 				fmt.Print(result)
 			}
@@ -81,6 +105,15 @@ Supports various Go frameworks and pure projects.`,
 	generateCmd.Flags().StringP("output", "o", "", "Output file path (default: stdout)")
 	generateCmd.Flags().BoolP("recursive", "r", true, "Recursively analyze subdirectories")
 	generateCmd.Flags().IntP("max-depth", "d", 10, "Maximum depth for recursive generation")
+	generateCmd.Flags().String("dispatch", "static", "Interface method call resolution (static, cha)")
+	generateCmd.Flags().String("goos", runtime.GOOS, "Target operating system for build constraints")
+	generateCmd.Flags().String("goarch", runtime.GOARCH, "Target architecture for build constraints")
+	generateCmd.Flags().StringSlice("tags", nil, "Comma-separated list of additional build tags")
+	generateCmd.Flags().Bool("all-platforms", false, "Include files for every platform and annotate nodes with their build constraint")
+	generateCmd.Flags().StringSlice("entry", nil, "Additional entry point patterns, e.g. pkg/usecase/golang.*.Generate")
+	generateCmd.Flags().Bool("exported-as-entry", false, "Library mode: use every exported function and method as an entry point")
+	generateCmd.Flags().Int("follow-deps", 0, "Descend N package levels into dependency source found in vendor/ or the module cache (never downloads)")
+	generateCmd.Flags().Bool("include-tests", false, "Include _test.go files and detect Test, Benchmark, Fuzz, Example and TestMain entry points")
 
 	return generateCmd
 }
@@ -91,13 +124,16 @@ func InitGetGolangCmd(conf *config.Config) *cobra.Command {
 		Use:   "golang",
 		Short: "Get specific information from Golang project",
 		Long: `Get specific information from Golang project source code.
-Available subcommands: call-tree, functions, classes, variables, imports`,
+Available subcommands: call-tree, callers, functions, classes, implementations, interfaces-of, variables, imports`,
 	}
 
 	// サブコマンドを追加
 	getCmd.AddCommand(initGetCallTreeCmd(conf))
+	getCmd.AddCommand(initGetCallersCmd(conf))
 	getCmd.AddCommand(initGetFunctionsCmd(conf))
 	getCmd.AddCommand(initGetClassesCmd(conf))
+	getCmd.AddCommand(initGetImplementationsCmd(conf))
+	getCmd.AddCommand(initGetInterfacesOfCmd(conf))
 	getCmd.AddCommand(initGetVariablesCmd(conf))
 	getCmd.AddCommand(initGetImportsCmd(conf))
 
@@ -116,6 +152,11 @@ Available options: --type (functions, classes, variables, imports)`,
 			itemType, _ := cmd.Flags().GetString("type")
 			recursive, _ := cmd.Flags().GetBool("recursive")
 			format, _ := cmd.Flags().GetString("format")
+			functionOpts := FunctionListOptions{}
+			functionOpts.Package, _ = cmd.Flags().GetString("package")
+			functionOpts.Receiver, _ = cmd.Flags().GetString("receiver")
+			functionOpts.Name, _ = cmd.Flags().GetString("name")
+			functionOpts.Sort, _ = cmd.Flags().GetString("sort")
 
 			if sourcePath == "" && len(args) > 0 {
 				sourcePath = args[0]
@@ -135,7 +176,7 @@ Available options: --type (functions, classes, variables, imports)`,
 
 			switch itemType {
 			case "functions", "func":
-				result, err = ListFunctions(conf, req, format)
+				result, err = ListFunctions(conf, req, format, functionOpts)
 			case "classes", "class", "types":
 				result, err = ListClasses(conf, req, format)
 			case "variables", "var":
@@ -147,7 +188,7 @@ Available options: --type (functions, classes, variables, imports)`,
 			}
 
 			if err != nil {
-				fmt.Printf("Error: %v\n", err)
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				return
 			}
 
@@ -159,17 +200,71 @@ Available options: --type (functions, classes, variables, imports)`,
 	listCmd.Flags().StringP("type", "t", "functions", "Type of items to list (functions, classes, variables, imports)")
 	listCmd.Flags().StringP("format", "f", "table", "Output format (table, json, yaml)")
 	listCmd.Flags().BoolP("recursive", "r", true, "Recursively analyze subdirectories")
+	listCmd.Flags().String("package", "", "Only list functions whose package name or import path matches this regular expression")
+	listCmd.Flags().String("receiver", "", "Only list methods whose receiver type matches this regular expression")
+	listCmd.Flags().String("name", "", "Only list functions whose name matches this regular expression")
+	listCmd.Flags().String("sort", "name", "Sort functions by name, fan-in, fan-out or lines")
 
 	return listCmd
 }
 
+// InitPathCmd creates the command searching call paths in a generated ctree file
+func InitPathCmd(conf *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "path",
+		Short: "Find call paths between two functions",
+		Long: `Find how control gets from one function to another in the call graph of a generated
+ctree file. The shortest path is shown unless --all is given, and every hop is shown
+with the file and line of its call.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctreePath, _ := cmd.Flags().GetString("ctree")
+			from, _ := cmd.Flags().GetString("from")
+			to, _ := cmd.Flags().GetString("to")
+			format, _ := cmd.Flags().GetString("format")
+			all, _ := cmd.Flags().GetBool("all")
+			limit, _ := cmd.Flags().GetInt("limit")
+			maxHops, _ := cmd.Flags().GetInt("max-hops")
+
+			opts := golang_usecase.CallPathOptions{
+				All:     all,
+				Limit:   limit,
+				MaxHops: maxHops,
+			}
+			result, err := GetCallPath(conf, ctreePath, from, to, format, opts)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return
+			}
+			fmt.Print(result)
+		},
+	}
+
+	cmd.Flags().StringP("ctree", "c", "", "Path to ctree YAML file (required)")
+	cmd.Flags().String("from", "", "Function the paths start at (required)")
+	cmd.Flags().String("to", "", "Function the paths end at (required)")
+	cmd.Flags().String("format", "text", "Output format (text, json, yaml)")
+	cmd.Flags().Bool("all", false, "Show every simple path instead of only the shortest one")
+	cmd.Flags().Int("limit", 10, "Maximum number of paths shown with --all, shortest first (0: no limit)")
+	cmd.Flags().Int("max-hops", 10, "Maximum number of calls in a path with --all")
+	cmd.MarkFlagRequired("ctree")
+	cmd.MarkFlagRequired("from")
+	cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
 // サブコマンド実装
 func initGetFunctionsCmd(conf *config.Config) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "functions [function_name]",
 		Short: "Get specific function information",
+		Long: `Show the signature, doc comment, source range, direct callers with call sites, direct callees
+and entry point reachability of functions. The name may be qualified with the receiver type,
+package name or import path.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			sourcePath, _ := cmd.Flags().GetString("source")
+			ctreePath, _ := cmd.Flags().GetString("ctree")
+			format, _ := cmd.Flags().GetString("format")
 			if sourcePath == "" {
 				sourcePath = "."
 			}
@@ -182,24 +277,33 @@ func initGetFunctionsCmd(conf *config.Config) *cobra.Command {
 			req := request.GenerateRequest{
 				SourcePath: sourcePath,
 				Recursive:  true,
+				MaxDepth:   10,
 			}
 
-			result, err := GetFunction(conf, req, functionName, "yaml")
+			result, err := GetFunction(conf, req, ctreePath, functionName, format)
 			if err != nil {
-				fmt.Printf("Error: %v\n", err)
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				return
 			}
 			fmt.Print(result)
 		},
 	}
+
+	cmd.Flags().StringP("source", "s", ".", "Source directory or file to analyze")
+	cmd.Flags().StringP("ctree", "c", "", "Read functions from a generated ctree YAML file instead of analyzing the source")
+	cmd.Flags().String("format", "yaml", "Output format (table, json, yaml)")
+
+	return cmd
 }
 
 func initGetClassesCmd(conf *config.Config) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "classes [class_name]",
 		Short: "Get specific class/type information",
+		Long:  `Show the kind, fields with tags, embedded types, method set, location and doc comment of Go types`,
 		Run: func(cmd *cobra.Command, args []string) {
 			sourcePath, _ := cmd.Flags().GetString("source")
+			format, _ := cmd.Flags().GetString("format")
 			if sourcePath == "" {
 				sourcePath = "."
 			}
@@ -212,24 +316,100 @@ func initGetClassesCmd(conf *config.Config) *cobra.Command {
 			req := request.GenerateRequest{
 				SourcePath: sourcePath,
 				Recursive:  true,
+				MaxDepth:   10,
+			}
+
+			result, err := GetClass(conf, req, className, format)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return
+			}
+			fmt.Print(result)
+		},
+	}
+
+	cmd.Flags().StringP("source", "s", ".", "Source directory or file to analyze")
+	cmd.Flags().String("format", "yaml", "Output format (table, json, yaml)")
+
+	return cmd
+}
+
+func initGetImplementationsCmd(conf *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "implementations <interface_name>",
+		Short: "Get the types implementing an interface",
+		Long:  `List the types of the analyzed source whose method set, or the method set of a pointer to them, satisfies the interface`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			sourcePath, _ := cmd.Flags().GetString("source")
+			format, _ := cmd.Flags().GetString("format")
+			if sourcePath == "" {
+				sourcePath = "."
+			}
+
+			req := request.GenerateRequest{
+				SourcePath: sourcePath,
+				Recursive:  true,
+				MaxDepth:   10,
+			}
+
+			result, err := GetImplementations(conf, req, args[0], format)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return
+			}
+			fmt.Print(result)
+		},
+	}
+
+	cmd.Flags().StringP("source", "s", ".", "Source directory or file to analyze")
+	cmd.Flags().String("format", "table", "Output format (table, json, yaml)")
+
+	return cmd
+}
+
+func initGetInterfacesOfCmd(conf *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "interfaces-of <type_name>",
+		Short: "Get the interfaces a type implements",
+		Long:  `List the interfaces of the analyzed source satisfied by the type or by a pointer to it`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			sourcePath, _ := cmd.Flags().GetString("source")
+			format, _ := cmd.Flags().GetString("format")
+			if sourcePath == "" {
+				sourcePath = "."
+			}
+
+			req := request.GenerateRequest{
+				SourcePath: sourcePath,
+				Recursive:  true,
+				MaxDepth:   10,
 			}
 
-			result, err := GetClass(conf, req, className, "yaml")
+			result, err := GetInterfacesOf(conf, req, args[0], format)
 			if err != nil {
-				fmt.Printf("Error: %v\n", err)
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				return
 			}
 			fmt.Print(result)
 		},
 	}
+
+	cmd.Flags().StringP("source", "s", ".", "Source directory or file to analyze")
+	cmd.Flags().String("format", "table", "Output format (table, json, yaml)")
+
+	return cmd
 }
 
 func initGetVariablesCmd(conf *config.Config) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "variables [variable_name]",
 		Short: "Get specific variable information",
+		Long:  `Show the type, initial value, location and doc comment of package-level variables and constants, and the functions reading or writing each variable`,
 		Run: func(cmd *cobra.Command, args []string) {
 			sourcePath, _ := cmd.Flags().GetString("source")
+			format, _ := cmd.Flags().GetString("format")
 			if sourcePath == "" {
 				sourcePath = "."
 			}
@@ -242,24 +422,32 @@ func initGetVariablesCmd(conf *config.Config) *cobra.Command {
 			req := request.GenerateRequest{
 				SourcePath: sourcePath,
 				Recursive:  true,
+				MaxDepth:   10,
 			}
 
-			result, err := GetVariable(conf, req, variableName, "yaml")
+			result, err := GetVariable(conf, req, variableName, format)
 			if err != nil {
-				fmt.Printf("Error: %v\n", err)
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				return
 			}
 			fmt.Print(result)
 		},
 	}
+
+	cmd.Flags().StringP("source", "s", ".", "Source directory or file to analyze")
+	cmd.Flags().String("format", "yaml", "Output format (table, json, yaml)")
+
+	return cmd
 }
 
 func initGetImportsCmd(conf *config.Config) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "imports",
 		Short: "Get import information",
+		Long:  `Report the imports of every file and package with aliases, blank and dot imports, and the split between standard library, internal and third-party packages`,
 		Run: func(cmd *cobra.Command, args []string) {
 			sourcePath, _ := cmd.Flags().GetString("source")
+			format, _ := cmd.Flags().GetString("format")
 			if sourcePath == "" {
 				sourcePath = "."
 			}
@@ -267,16 +455,88 @@ func initGetImportsCmd(conf *config.Config) *cobra.Command {
 			req := request.GenerateRequest{
 				SourcePath: sourcePath,
 				Recursive:  true,
+				MaxDepth:   10,
 			}
 
-			result, err := GetImports(conf, req, "yaml")
+			result, err := GetImports(conf, req, format)
 			if err != nil {
-				fmt.Printf("Error: %v\n", err)
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				return
 			}
 			fmt.Print(result)
 		},
 	}
+
+	cmd.Flags().StringP("source", "s", ".", "Source directory or file to analyze")
+	cmd.Flags().String("format", "yaml", "Output format (table, json, yaml)")
+
+	return cmd
+}
+
+// initGetCallersCmd creates a get callers command
+func initGetCallersCmd(conf *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "callers <function_name>",
+		Short: "Get the reverse call tree of a function",
+		Long: `Show every chain of callers leading to a function, up to the entry points.
+The function may be outside the analyzed source, e.g. os.Exit. The build options
+select the analyzed files as in generate, and are ignored with --ctree.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			sourcePath, _ := cmd.Flags().GetString("source")
+			ctreePath, _ := cmd.Flags().GetString("ctree")
+			format, _ := cmd.Flags().GetString("format")
+			expandSignature, _ := cmd.Flags().GetBool("expand-signature")
+			dispatch, _ := cmd.Flags().GetString("dispatch")
+			goos, _ := cmd.Flags().GetString("goos")
+			goarch, _ := cmd.Flags().GetString("goarch")
+			tags, _ := cmd.Flags().GetStringSlice("tags")
+			allPlatforms, _ := cmd.Flags().GetBool("all-platforms")
+			includeTests, _ := cmd.Flags().GetBool("include-tests")
+			followDeps, _ := cmd.Flags().GetInt("follow-deps")
+			if sourcePath == "" {
+				sourcePath = "."
+			}
+			if allPlatforms && (cmd.Flags().Changed("goos") || cmd.Flags().Changed("goarch") || cmd.Flags().Changed("tags")) {
+				fmt.Fprintln(os.Stderr, "Error: --all-platforms selects files for every platform and cannot be combined with --goos, --goarch or --tags")
+				return
+			}
+
+			req := request.GenerateRequest{
+				SourcePath:   sourcePath,
+				Recursive:    true,
+				MaxDepth:     10,
+				Dispatch:     dispatch,
+				GOOS:         goos,
+				GOARCH:       goarch,
+				Tags:         tags,
+				AllPlatforms: allPlatforms,
+				IncludeTests: includeTests,
+				FollowDeps:   followDeps,
+			}
+
+			result, err := GetCallers(conf, req, ctreePath, args[0], format, expandSignature)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return
+			}
+			fmt.Print(result)
+		},
+	}
+
+	cmd.Flags().StringP("source", "s", ".", "Source directory or file to analyze")
+	cmd.Flags().StringP("ctree", "c", "", "Read the call graph from a generated ctree YAML file instead of analyzing the source")
+	cmd.Flags().String("format", "text", "Output format (text, json, yaml)")
+	cmd.Flags().Bool("expand-signature", false, "Show function parameters and return values on separate lines")
+	cmd.Flags().String("dispatch", "static", "Interface method call resolution (static, cha)")
+	cmd.Flags().String("goos", runtime.GOOS, "Target operating system for build constraints")
+	cmd.Flags().String("goarch", runtime.GOARCH, "Target architecture for build constraints")
+	cmd.Flags().StringSlice("tags", nil, "Comma-separated list of additional build tags")
+	cmd.Flags().Bool("all-platforms", false, "Include files for every platform and annotate nodes with their build constraint")
+	cmd.Flags().Int("follow-deps", 0, "Descend N package levels into dependency source found in vendor/ or the module cache (never downloads)")
+	cmd.Flags().Bool("include-tests", false, "Include _test.go files and detect Test, Benchmark, Fuzz, Example and TestMain entry points")
+
+	return cmd
 }
 
 // initGetCallTreeCmd creates a get call-tree command
@@ -291,9 +551,16 @@ func initGetCallTreeCmd(conf *config.Config) *cobra.Command {
 			outputPath, _ := cmd.Flags().GetString("output")
 			format, _ := cmd.Flags().GetString("format")
 			expandSignature, _ := cmd.Flags().GetBool("expand-signature")
+			entryKinds, _ := cmd.Flags().GetStringSlice("entry-kind")
+			hideOrigins, _ := cmd.Flags().GetStringSlice("hide")
+			showBuiltins, _ := cmd.Flags().GetBool("show-builtins")
+			entry, _ := cmd.Flags().GetString("entry")
+			root, _ := cmd.Flags().GetString("root")
+			depth, _ := cmd.Flags().GetInt("depth")
+			collapseExternal, _ := cmd.Flags().GetBool("collapse-external")
 
 			if ctreePath == "" {
-				fmt.Println("Error: --ctree flag is required")
+				fmt.Fprintln(os.Stderr, "Error: --ctree flag is required")
 				cmd.Usage()
 				return
 			}
@@ -303,9 +570,19 @@ func initGetCallTreeCmd(conf *config.Config) *cobra.Command {
 				Framework:  framework,
 			}
 
-			result, err := GetCallTree(conf, req, format, expandSignature)
+			opts := CallTreeOptions{
+				ExpandSignature:  expandSignature,
+				EntryKinds:       entryKinds,
+				HideOrigins:      hideOrigins,
+				ShowBuiltins:     showBuiltins,
+				Entry:            entry,
+				Root:             root,
+				Depth:            depth,
+				CollapseExternal: collapseExternal,
+			}
+			result, err := GetCallTree(conf, req, format, opts)
 			if err != nil {
-				fmt.Printf("Error: %v\n", err)
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				return
 			}
 
@@ -313,10 +590,10 @@ func initGetCallTreeCmd(conf *config.Config) *cobra.Command {
 			if outputPath != "" {
 				err = os.WriteFile(outputPath, []byte(result), 0644)
 				if err != nil {
-					fmt.Printf("Error writing to file %s: %v\n", outputPath, err)
+					fmt.Fprintf(os.Stderr, "Error writing to file %s: %v\n", outputPath, err)
 					return
 				}
-				fmt.Printf("Output written to %s\n", outputPath)
+				fmt.Fprintf(os.Stderr, "Output written to %s\n", outputPath)
 			} else {
 				fmt.Print(result)
 			}
@@ -328,6 +605,13 @@ func initGetCallTreeCmd(conf *config.Config) *cobra.Command {
 	cmd.Flags().StringP("output", "o", "", "Output file path (default: stdout)")
 	cmd.Flags().String("format", "yaml", "Output format (yaml, json, text)")
 	cmd.Flags().Bool("expand-signature", false, "Show function parameters and return values on separate lines")
+	cmd.Flags().StringSlice("entry-kind", nil, "Only show entry points of these kinds (entrypoint, initializer, test, benchmark, fuzz, example, testmain, entry for --entry and //ctree:entry, exported for --exported-as-entry)")
+	cmd.Flags().StringSlice("hide", nil, "Hide external callees of these origins (stdlib, third-party, builtin, internal)")
+	cmd.Flags().Bool("show-builtins", false, "Show builtin calls such as len and append, and type conversions")
+	cmd.Flags().String("entry", "", "Only show one entry point, by number (1, 2, ...) or function name")
+	cmd.Flags().String("root", "", "Show the subtree rooted at this function instead of the entry points")
+	cmd.Flags().Int("depth", 0, "Truncate the tree below this depth (0: no limit)")
+	cmd.Flags().Bool("collapse-external", false, "Fold runs of consecutive external calls into one line")
 	cmd.MarkFlagRequired("ctree")
 
 	return cmd
@@ -0,0 +1,45 @@
+package golang
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// formatOutput renders data as JSON or YAML, or as a table written by table
+func formatOutput(format string, data interface{}, table func(w *tabwriter.Writer)) (string, error) {
+	switch strings.ToLower(format) {
+	case "table", "text", "":
+		var result strings.Builder
+		w := tabwriter.NewWriter(&result, 0, 0, 2, ' ', 0)
+		table(w)
+		if err := w.Flush(); err != nil {
+			return "", fmt.Errorf("failed to write table: %w", err)
+		}
+		return result.String(), nil
+	case "json":
+		output, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal to JSON: %w", err)
+		}
+		return string(output) + "\n", nil
+	case "yaml", "yml":
+		output, err := yaml.Marshal(data)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal to YAML: %w", err)
+		}
+		return string(output), nil
+	default:
+		return "", fmt.Errorf("unsupported format: %s (supported: table, json, yaml)", format)
+	}
+}
+
+// matchQualifiedName reports whether query names a declaration, either by its bare name
+// or qualified with its package name or import path, as in Service, golang.Service or
+// github.com/ryo-arima/ctree/pkg/usecase/golang.Service
+func matchQualifiedName(query, name, packageName, packagePath string) bool {
+	return query == name || query == packageName+"."+name || query == packagePath+"."+name
+}
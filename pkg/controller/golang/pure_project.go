@@ -3,7 +3,11 @@ package golang
 import (
 	"fmt"
 	"os"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/ryo-arima/ctree/pkg/config"
 	"github.com/ryo-arima/ctree/pkg/entity/model"
@@ -34,98 +38,705 @@ func GeneratePureProject(conf *config.Config, req request.GenerateRequest, forma
 	return uc.Generate(req, format)
 }
 
+// FunctionListOptions filters and orders the functions listed by ListFunctions
+type FunctionListOptions struct {
+	Package  string // Regular expression matched against the package name or import path
+	Receiver string // Regular expression matched against the receiver type
+	Name     string // Regular expression matched against the function name
+	Sort     string // name, fan-in, fan-out or lines
+}
+
 // ListFunctions lists all functions in the project
-func ListFunctions(conf *config.Config, req request.GenerateRequest, format string) (string, error) {
+func ListFunctions(conf *config.Config, req request.GenerateRequest, format string, opts FunctionListOptions) (string, error) {
+	packagePattern, err := compilePattern("package", opts.Package)
+	if err != nil {
+		return "", err
+	}
+	receiverPattern, err := compilePattern("receiver", opts.Receiver)
+	if err != nil {
+		return "", err
+	}
+	namePattern, err := compilePattern("name", opts.Name)
+	if err != nil {
+		return "", err
+	}
+
 	uc := golang_usecase.NewGoPureProjectGenerateUsecase(conf)
-	// TODO: Implement function listing logic using ctags
-	_ = uc // suppress unused variable warning for now
-	return fmt.Sprintf("Functions list for %s (format: %s)", req.SourcePath, format), nil
+	ctree, err := uc.Analyze(req)
+	if err != nil {
+		return "", err
+	}
+
+	var functions []model.FunctionSummary
+	for _, fn := range uc.SummarizeFunctions(ctree) {
+		if packagePattern != nil && !packagePattern.MatchString(fn.Package) && !packagePattern.MatchString(fn.PackagePath) {
+			continue
+		}
+		if receiverPattern != nil && !receiverPattern.MatchString(fn.Receiver) {
+			continue
+		}
+		if namePattern != nil && !namePattern.MatchString(fn.Name) {
+			continue
+		}
+		functions = append(functions, fn)
+	}
+	if err := sortFunctions(functions, opts.Sort); err != nil {
+		return "", err
+	}
+
+	return formatOutput(format, functions, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "NAME\tRECEIVER\tPACKAGE\tSIGNATURE\tEXPORTED\tFAN-IN\tFAN-OUT\tLINES\tLOCATION")
+		for _, fn := range functions {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\t%d\t%d\t%d\t%s:%d\n", fn.Name, fn.Receiver, fn.Package, fn.Signature, fn.Exported, fn.FanIn, fn.FanOut, fn.Lines, fn.File, fn.Line)
+		}
+	})
+}
+
+// compilePattern compiles the regular expression of a filter flag, returning nil for an empty pattern
+func compilePattern(flag, pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%s pattern %q: %w", flag, pattern, err)
+	}
+	return re, nil
+}
+
+// sortFunctions orders functions by name, or by fan-in, fan-out or lines with the largest first.
+// Ties keep the order of the qualified function key.
+func sortFunctions(functions []model.FunctionSummary, by string) error {
+	var less func(a, b model.FunctionSummary) bool
+	switch by {
+	case "name", "":
+		less = func(a, b model.FunctionSummary) bool { return a.Name < b.Name }
+	case "fan-in":
+		less = func(a, b model.FunctionSummary) bool { return a.FanIn > b.FanIn }
+	case "fan-out":
+		less = func(a, b model.FunctionSummary) bool { return a.FanOut > b.FanOut }
+	case "lines":
+		less = func(a, b model.FunctionSummary) bool { return a.Lines > b.Lines }
+	default:
+		return fmt.Errorf("unsupported sort key: %s (supported: name, fan-in, fan-out, lines)", by)
+	}
+
+	sort.SliceStable(functions, func(i, j int) bool {
+		if less(functions[i], functions[j]) {
+			return true
+		}
+		if less(functions[j], functions[i]) {
+			return false
+		}
+		return functions[i].Key < functions[j].Key
+	})
+	return nil
 }
 
 // ListClasses lists all classes/types in the project
 func ListClasses(conf *config.Config, req request.GenerateRequest, format string) (string, error) {
 	uc := golang_usecase.NewGoPureProjectGenerateUsecase(conf)
-	// TODO: Implement class/type listing logic using ctags
-	_ = uc // suppress unused variable warning for now
-	return fmt.Sprintf("Classes/Types list for %s (format: %s)", req.SourcePath, format), nil
+	classes, err := uc.ListClasses(req)
+	if err != nil {
+		return "", err
+	}
+	return formatOutput(format, classes, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "NAME\tKIND\tPACKAGE\tFIELDS\tMETHODS\tLOCATION")
+		for _, class := range classes {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%s:%d\n", class.Name, class.Kind, class.Package, len(class.Fields), len(class.Methods), class.File, class.Line)
+		}
+	})
 }
 
 // ListVariables lists all variables in the project
 func ListVariables(conf *config.Config, req request.GenerateRequest, format string) (string, error) {
 	uc := golang_usecase.NewGoPureProjectGenerateUsecase(conf)
-	// TODO: Implement variable listing logic using ctags
-	_ = uc // suppress unused variable warning for now
-	return fmt.Sprintf("Variables list for %s (format: %s)", req.SourcePath, format), nil
+	variables, err := uc.ListVariables(req)
+	if err != nil {
+		return "", err
+	}
+	return formatOutput(format, variables, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "NAME\tKIND\tTYPE\tVALUE\tEXPORTED\tREFERENCED BY\tLOCATION")
+		for _, variable := range variables {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\t%d\t%s:%d\n", variable.Name, variable.Kind, variable.Type, formatVariableValue(variable), variable.Exported, len(variable.ReferencedBy), variable.File, variable.Line)
+		}
+	})
 }
 
 // ListImports lists all imports in the project
 func ListImports(conf *config.Config, req request.GenerateRequest, format string) (string, error) {
 	uc := golang_usecase.NewGoPureProjectGenerateUsecase(conf)
-	// TODO: Implement import listing logic using ctags
-	_ = uc // suppress unused variable warning for now
-	return fmt.Sprintf("Imports list for %s (format: %s)", req.SourcePath, format), nil
+	report, err := uc.ListImports(req)
+	if err != nil {
+		return "", err
+	}
+	return formatOutput(format, report, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "PATH\tORIGIN\tMODULE\tFILES\tPACKAGES\tALIASES\tBLANK\tDOT")
+		for _, summary := range report.Paths {
+			module := summary.Module
+			if summary.ModuleVersion != "" {
+				module += "@" + summary.ModuleVersion
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%s\t%d\t%d\n", summary.Path, summary.Origin, module, summary.Files, summary.Packages, strings.Join(summary.Aliases, ","), summary.Blank, summary.Dot)
+		}
+		fmt.Fprintln(w)
+		formatImportOrigins(w, report)
+	})
 }
 
-// GetFunction gets specific function information
-func GetFunction(conf *config.Config, req request.GenerateRequest, functionName string, format string) (string, error) {
+// GetFunction gets specific function information, from a generated ctree file when
+// ctreePath is set and by analyzing the source otherwise
+func GetFunction(conf *config.Config, req request.GenerateRequest, ctreePath string, functionName string, format string) (string, error) {
 	uc := golang_usecase.NewGoPureProjectGenerateUsecase(conf)
-	// TODO: Implement specific function retrieval logic using ctags
-	_ = uc // suppress unused variable warning for now
-	if functionName == "" {
-		return fmt.Sprintf("All functions in %s (format: %s)", req.SourcePath, format), nil
+
+	var ctree *model.CTree
+	var err error
+	if ctreePath != "" {
+		ctree, err = loadCTree(ctreePath)
+	} else {
+		ctree, err = uc.Analyze(req)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	details := uc.DescribeFunctions(ctree, func(summary model.FunctionSummary) bool {
+		return functionName == "" || matchFunctionName(functionName, summary)
+	})
+	if len(details) == 0 {
+		return "", fmt.Errorf("function not found: %s", functionName)
 	}
-	return fmt.Sprintf("Function '%s' details from %s (format: %s)", functionName, req.SourcePath, format), nil
+
+	return formatOutput(format, details, func(w *tabwriter.Writer) {
+		for i, detail := range details {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			formatFunctionDetails(w, detail)
+		}
+	})
+}
+
+// matchFunctionName reports whether query names a function, either by its bare name or
+// qualified with its receiver type, package name or import path, as in Generate,
+// goPureProjectGenerateUsecase.Generate or golang.goPureProjectGenerateUsecase.Generate
+func matchFunctionName(query string, fn model.FunctionSummary) bool {
+	if matchQualifiedName(query, fn.Name, fn.Package, fn.PackagePath) {
+		return true
+	}
+	return fn.Receiver != "" && matchQualifiedName(query, fn.Receiver+"."+fn.Name, fn.Package, fn.PackagePath)
+}
+
+// formatFunctionDetails writes the signature, location, callers and callees of a function
+func formatFunctionDetails(w *tabwriter.Writer, detail model.FunctionDetail) {
+	fmt.Fprintln(w, detail.Signature)
+	location := fmt.Sprintf("%s:%d", detail.File, detail.Line)
+	if detail.EndLine > detail.Line {
+		location = fmt.Sprintf("%s:%d-%d (%d lines)", detail.File, detail.Line, detail.EndLine, detail.Lines)
+	}
+	fmt.Fprintf(w, "  %s\t%s\n", detail.PackagePath, location)
+	if detail.Doc != "" {
+		for _, line := range strings.Split(detail.Doc, "\n") {
+			fmt.Fprintf(w, "  // %s\n", line)
+		}
+	}
+	if detail.Reachable {
+		fmt.Fprintf(w, "  Reachable from: %s\n", strings.Join(detail.ReachableFrom, ", "))
+	} else {
+		fmt.Fprintln(w, "  Not reachable from any entry point")
+	}
+
+	fmt.Fprintf(w, "  Callers (%d):\n", len(detail.Callers))
+	for _, call := range detail.Callers {
+		fmt.Fprintf(w, "    %s\t%s\t%s\n", call.Function, formatCallNotes(call), formatCallSites(call.Sites))
+	}
+	fmt.Fprintf(w, "  Callees (%d):\n", len(detail.Callees))
+	for _, call := range detail.Callees {
+		fmt.Fprintf(w, "    %s\t%s\t%s\n", call.Function, formatCallNotes(call), formatCallSites(call.Sites))
+	}
+}
+
+// formatCallNotes describes how a call is made, e.g. "go, dynamic" or "external"
+func formatCallNotes(call model.FunctionCall) string {
+	var notes []string
+	if call.CallKind != "" && call.CallKind != "call" {
+		notes = append(notes, call.CallKind)
+	}
+	if call.Dynamic {
+		notes = append(notes, "dynamic")
+	}
+	if call.Field {
+		notes = append(notes, "field")
+	}
+	if call.External {
+		notes = append(notes, "external")
+	}
+	return strings.Join(notes, ", ")
+}
+
+// formatCallSites lists call sites as file:line, leaving out the file when it repeats
+func formatCallSites(sites []model.CallSite) string {
+	var locations []string
+	lastFile := ""
+	for _, site := range sites {
+		if site.File == lastFile {
+			locations = append(locations, fmt.Sprintf(":%d", site.Line))
+			continue
+		}
+		locations = append(locations, fmt.Sprintf("%s:%d", site.File, site.Line))
+		lastFile = site.File
+	}
+	return strings.Join(locations, ", ")
 }
 
 // GetClass gets specific class/type information
 func GetClass(conf *config.Config, req request.GenerateRequest, className string, format string) (string, error) {
 	uc := golang_usecase.NewGoPureProjectGenerateUsecase(conf)
-	// TODO: Implement specific class/type retrieval logic using ctags
-	_ = uc // suppress unused variable warning for now
-	if className == "" {
-		return fmt.Sprintf("All classes/types in %s (format: %s)", req.SourcePath, format), nil
+	classes, err := uc.ListClasses(req)
+	if err != nil {
+		return "", err
+	}
+
+	// The name may be qualified with the package name or import path
+	if className != "" {
+		var matched []model.Class
+		for _, class := range classes {
+			if matchQualifiedName(className, class.Name, class.Package, class.PackagePath) {
+				matched = append(matched, class)
+			}
+		}
+		if len(matched) == 0 {
+			return "", fmt.Errorf("type not found: %s", className)
+		}
+		classes = matched
 	}
-	return fmt.Sprintf("Class/Type '%s' details from %s (format: %s)", className, req.SourcePath, format), nil
+
+	return formatOutput(format, classes, func(w *tabwriter.Writer) {
+		for i, class := range classes {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			formatClassDetails(w, class)
+		}
+	})
+}
+
+// formatClassDetails writes the fields, embedded types and method set of a type
+func formatClassDetails(w *tabwriter.Writer, class model.Class) {
+	header := fmt.Sprintf("type %s.%s %s", class.Package, class.Name, class.Kind)
+	if class.Underlying != "" {
+		header = fmt.Sprintf("type %s.%s %s (%s)", class.Package, class.Name, class.Underlying, class.Kind)
+	}
+	fmt.Fprintf(w, "%s\t(%s:%d)\n", header, class.File, class.Line)
+	if class.Doc != "" {
+		for _, line := range strings.Split(class.Doc, "\n") {
+			fmt.Fprintf(w, "  // %s\n", line)
+		}
+	}
+	if len(class.TypeParams) > 0 {
+		fmt.Fprintln(w, "  Type Parameters:")
+		for _, typeParam := range class.TypeParams {
+			fmt.Fprintf(w, "    %s\t%s\n", typeParam.Name, typeParam.Type)
+		}
+	}
+	if len(class.Fields) > 0 {
+		fmt.Fprintln(w, "  Fields:")
+		for _, field := range class.Fields {
+			name := field.Name
+			if field.Embedded {
+				name += " (embedded)"
+			}
+			fmt.Fprintf(w, "    %s\t%s\t%s\n", name, field.Type, field.Tag)
+		}
+	}
+	if len(class.Embeds) > 0 && class.Kind == "interface" {
+		fmt.Fprintf(w, "  Embeds: %s\n", strings.Join(class.Embeds, ", "))
+	}
+	if len(class.Methods) > 0 {
+		fmt.Fprintln(w, "  Methods:")
+		for _, method := range class.Methods {
+			var notes []string
+			if method.Pointer {
+				notes = append(notes, "pointer receiver")
+			}
+			if method.Via != "" {
+				notes = append(notes, "via "+method.Via)
+			}
+			fmt.Fprintf(w, "    %s%s\t%s\n", method.Name, method.Signature, strings.Join(notes, ", "))
+		}
+	}
+}
+
+// GetImplementations lists the types of the source that implement an interface
+func GetImplementations(conf *config.Config, req request.GenerateRequest, interfaceName string, format string) (string, error) {
+	uc := golang_usecase.NewGoPureProjectGenerateUsecase(conf)
+	implementations, err := uc.ListImplementations(req)
+	if err != nil {
+		return "", err
+	}
+
+	var matched []model.Implementation
+	for _, impl := range implementations {
+		if matchQualifiedName(interfaceName, impl.Interface, impl.InterfacePackage, impl.InterfacePackagePath) {
+			matched = append(matched, impl)
+		}
+	}
+	if len(matched) == 0 {
+		return "", fmt.Errorf("no implementations found for interface: %s", interfaceName)
+	}
+
+	return formatOutput(format, matched, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "INTERFACE\tTYPE\tLOCATION")
+		for _, impl := range matched {
+			fmt.Fprintf(w, "%s.%s\t%s\t%s:%d\n", impl.InterfacePackage, impl.Interface, formatImplementingType(impl), impl.File, impl.Line)
+		}
+	})
+}
+
+// GetInterfacesOf lists the interfaces of the source that a type implements
+func GetInterfacesOf(conf *config.Config, req request.GenerateRequest, typeName string, format string) (string, error) {
+	uc := golang_usecase.NewGoPureProjectGenerateUsecase(conf)
+	implementations, err := uc.ListImplementations(req)
+	if err != nil {
+		return "", err
+	}
+
+	var matched []model.Implementation
+	for _, impl := range implementations {
+		if matchQualifiedName(typeName, impl.Type, impl.Package, impl.PackagePath) {
+			matched = append(matched, impl)
+		}
+	}
+	if len(matched) == 0 {
+		return "", fmt.Errorf("no interfaces found for type: %s", typeName)
+	}
+
+	return formatOutput(format, matched, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "TYPE\tINTERFACE\tLOCATION")
+		for _, impl := range matched {
+			fmt.Fprintf(w, "%s\t%s.%s\t%s:%d\n", formatImplementingType(impl), impl.InterfacePackage, impl.Interface, impl.InterfaceFile, impl.InterfaceLine)
+		}
+	})
+}
+
+// formatImplementingType returns the qualified implementing type, as a pointer
+// when only the pointer type has the methods of the interface
+func formatImplementingType(impl model.Implementation) string {
+	name := impl.Package + "." + impl.Type
+	if impl.Pointer {
+		return "*" + name
+	}
+	return name
 }
 
 // GetVariable gets specific variable information
 func GetVariable(conf *config.Config, req request.GenerateRequest, variableName string, format string) (string, error) {
 	uc := golang_usecase.NewGoPureProjectGenerateUsecase(conf)
-	// TODO: Implement specific variable retrieval logic using ctags
-	_ = uc // suppress unused variable warning for now
-	if variableName == "" {
-		return fmt.Sprintf("All variables in %s (format: %s)", req.SourcePath, format), nil
+	variables, err := uc.ListVariables(req)
+	if err != nil {
+		return "", err
+	}
+
+	// The name may be qualified with the package name or import path
+	if variableName != "" {
+		var matched []model.Variable
+		for _, variable := range variables {
+			if matchQualifiedName(variableName, variable.Name, variable.Package, variable.PackagePath) {
+				matched = append(matched, variable)
+			}
+		}
+		if len(matched) == 0 {
+			return "", fmt.Errorf("variable not found: %s", variableName)
+		}
+		variables = matched
+	}
+
+	return formatOutput(format, variables, func(w *tabwriter.Writer) {
+		for i, variable := range variables {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			formatVariableDetails(w, variable)
+		}
+	})
+}
+
+// formatVariableValue returns the initial value of a variable, followed by the
+// computed value for iota constants
+func formatVariableValue(variable model.Variable) string {
+	if variable.Constant != "" {
+		return fmt.Sprintf("%s (= %s)", variable.Value, variable.Constant)
+	}
+	return variable.Value
+}
+
+// formatVariableDetails writes the declaration of a variable and the functions referencing it
+func formatVariableDetails(w *tabwriter.Writer, variable model.Variable) {
+	declaration := fmt.Sprintf("%s %s.%s", variable.Kind, variable.Package, variable.Name)
+	if variable.Type != "" {
+		declaration += " " + variable.Type
+	}
+	if variable.Value != "" {
+		declaration += " = " + formatVariableValue(variable)
+	}
+	fmt.Fprintf(w, "%s\t(%s:%d)\n", declaration, variable.File, variable.Line)
+	if variable.Doc != "" {
+		for _, line := range strings.Split(variable.Doc, "\n") {
+			fmt.Fprintf(w, "  // %s\n", line)
+		}
+	}
+	if len(variable.ReferencedBy) > 0 {
+		fmt.Fprintln(w, "  Referenced by:")
+		for _, ref := range variable.ReferencedBy {
+			fmt.Fprintf(w, "    %s\t%s\t%s:%d\n", ref.Function, ref.Access, ref.File, ref.Line)
+		}
 	}
-	return fmt.Sprintf("Variable '%s' details from %s (format: %s)", variableName, req.SourcePath, format), nil
 }
 
 // GetImports gets import information
 func GetImports(conf *config.Config, req request.GenerateRequest, format string) (string, error) {
 	uc := golang_usecase.NewGoPureProjectGenerateUsecase(conf)
-	// TODO: Implement import retrieval logic using ctags
-	_ = uc // suppress unused variable warning for now
-	return fmt.Sprintf("Imports from %s (format: %s)", req.SourcePath, format), nil
+	report, err := uc.ListImports(req)
+	if err != nil {
+		return "", err
+	}
+	return formatOutput(format, report, func(w *tabwriter.Writer) {
+		for _, file := range report.Files {
+			fmt.Fprintf(w, "%s (package %s)\n", file.File, file.Package)
+			for _, imp := range file.Imports {
+				module := imp.Module
+				if imp.ModuleVersion != "" {
+					module += "@" + imp.ModuleVersion
+				}
+				spec := imp.Path
+				if imp.Name != "" {
+					spec = imp.Name + " " + imp.Path
+				}
+				fmt.Fprintf(w, "  %s\t%s\t%s\n", spec, imp.Origin, module)
+			}
+			fmt.Fprintln(w)
+		}
+
+		fmt.Fprintln(w, "PACKAGE\tFILES\tIMPORTS\tSTDLIB\tINTERNAL\tTHIRD PARTY")
+		for _, pkg := range report.Packages {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\n", pkg.PackagePath, pkg.Files, len(pkg.Imports), pkg.Stdlib, pkg.Internal, pkg.ThirdParty)
+		}
+		fmt.Fprintln(w)
+		formatImportOrigins(w, report)
+	})
 }
 
-// GetCallTree extracts call tree from a previously generated ctree YAML file
-func GetCallTree(conf *config.Config, req request.GenerateRequest, format string, expandSignature bool) (string, error) {
-	// Read the ctree YAML file
-	data, err := os.ReadFile(req.SourcePath)
+// formatImportOrigins writes the number of distinct import paths per origin
+func formatImportOrigins(w *tabwriter.Writer, report *model.ImportReport) {
+	fmt.Fprintf(w, "Import paths: %d stdlib, %d internal, %d third-party\n",
+		report.Origins["stdlib"], report.Origins["internal"], report.Origins["third_party"])
+}
+
+// loadCTree reads a ctree YAML file written by the generate command
+func loadCTree(path string) (*model.CTree, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return "", fmt.Errorf("failed to read ctree file: %w", err)
+		return nil, fmt.Errorf("failed to read ctree file: %w", err)
 	}
 
-	// Parse YAML
 	var ctree model.CTree
 	if err := yaml.Unmarshal(data, &ctree); err != nil {
-		return "", fmt.Errorf("failed to parse ctree YAML: %w", err)
+		return nil, fmt.Errorf("failed to parse ctree YAML: %w", err)
+	}
+	return &ctree, nil
+}
+
+// GetCallers builds the reverse call tree of a function up to the entry points, from a
+// generated ctree file when ctreePath is set and by analyzing the source otherwise
+func GetCallers(conf *config.Config, req request.GenerateRequest, ctreePath string, functionName string, format string, expandSignature bool) (string, error) {
+	uc := golang_usecase.NewGoPureProjectGenerateUsecase(conf)
+
+	var ctree *model.CTree
+	var err error
+	if ctreePath != "" {
+		ctree, err = loadCTree(ctreePath)
+	} else {
+		ctree, err = uc.Analyze(req)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	targets := findFunctionKeys(uc, ctree, functionName)
+	if len(targets) == 0 {
+		return "", fmt.Errorf("function not found: %s", functionName)
+	}
+	nodes := uc.BuildCallerTree(ctree, targets)
+
+	switch format {
+	case "text", "tree", "":
+		return formatCallerTreeAsText(nodes, expandSignature), nil
+	case "json", "yaml", "yml":
+		return formatOutput(format, map[string]interface{}{"caller_tree": nodes}, nil)
+	default:
+		return "", fmt.Errorf("unsupported format: %s (supported: text, json, yaml)", format)
+	}
+}
+
+// GetCallPath finds how calls get from one function to another in a generated ctree file
+func GetCallPath(conf *config.Config, ctreePath string, from string, to string, format string, opts golang_usecase.CallPathOptions) (string, error) {
+	ctree, err := loadCTree(ctreePath)
+	if err != nil {
+		return "", err
+	}
+
+	uc := golang_usecase.NewGoPureProjectGenerateUsecase(conf)
+	fromKeys := findFunctionKeys(uc, ctree, from)
+	if len(fromKeys) == 0 {
+		return "", fmt.Errorf("function not found: %s", from)
+	}
+	toKeys := findFunctionKeys(uc, ctree, to)
+	if len(toKeys) == 0 {
+		return "", fmt.Errorf("function not found: %s", to)
+	}
+
+	paths := uc.FindCallPaths(ctree, fromKeys, toKeys, opts)
+	switch format {
+	case "text", "":
+		if len(paths) == 0 {
+			return fmt.Sprintf("No call path from %s to %s\n", from, to), nil
+		}
+		var result strings.Builder
+		for i, path := range paths {
+			if i > 0 {
+				result.WriteString("\n")
+			}
+			formatCallPath(&result, i+1, path)
+		}
+		return result.String(), nil
+	case "json", "yaml", "yml":
+		return formatOutput(format, map[string]interface{}{"paths": paths}, nil)
+	default:
+		return "", fmt.Errorf("unsupported format: %s (supported: text, json, yaml)", format)
+	}
+}
+
+// formatCallPath writes a call path as a chain of callees with the call site of every hop
+func formatCallPath(result *strings.Builder, number int, path model.CallPath) {
+	result.WriteString(colorBold + colorYellow + fmt.Sprintf("Path %d (%d calls):", number, len(path.Hops)) + colorReset + "\n")
+	result.WriteString("  " + colorBrightCyan + path.From + colorReset + "\n")
+	for _, hop := range path.Hops {
+		result.WriteString("  " + colorBrightBlue + "→ " + colorReset + colorBrightCyan + hop.To + colorReset)
+		if hop.File != "" {
+			result.WriteString(" " + colorGray + fmt.Sprintf("@%s:%d", hop.File, hop.Line) + colorReset)
+		} else {
+			result.WriteString(" " + colorGray + "[external]" + colorReset)
+		}
+		if hop.Dynamic {
+			result.WriteString(colorMagenta + " [dynamic]" + colorReset)
+		}
+		if hop.Via != "" {
+			result.WriteString(colorCyan + fmt.Sprintf(" [via %s]", hop.Via) + colorReset)
+		}
+		switch hop.CallKind {
+		case "go":
+			result.WriteString(colorRed + " [go]" + colorReset)
+		case "defer":
+			result.WriteString(colorBlue + " [defer]" + colorReset)
+		case "ref":
+			result.WriteString(colorGray + " [ref]" + colorReset)
+		}
+		result.WriteString("\n")
+	}
+}
+
+// findFunctionKeys returns the keys of the analyzed functions matching name, or else the
+// keys of called functions outside the analyzed source such as os.Exit, given with their
+// package name or full import path
+func findFunctionKeys(uc golang_usecase.GoPureProjectGenerateUsecase, ctree *model.CTree, name string) []string {
+	var keys []string
+	for _, summary := range uc.SummarizeFunctions(ctree) {
+		if matchFunctionName(name, summary) {
+			keys = append(keys, summary.Key)
+		}
+	}
+	if len(keys) > 0 {
+		return keys
+	}
+
+	seen := make(map[string]bool)
+	for _, fn := range ctree.Functions {
+		for _, callee := range fn.CallsTo {
+			if !seen[callee] && (callee == name || strings.HasSuffix(callee, "/"+name)) {
+				seen[callee] = true
+				keys = append(keys, callee)
+			}
+		}
+	}
+	return keys
+}
+
+// CallTreeOptions controls which parts of a stored call tree are shown and how
+type CallTreeOptions struct {
+	ExpandSignature  bool     // show parameters and return values on separate lines
+	EntryKinds       []string // only show entry points of these kinds
+	HideOrigins      []string // hide external callees of these origins
+	ShowBuiltins     bool     // keep builtin calls and type conversions
+	Entry            string   // only show this entry point, by 1-based number or function name
+	Root             string   // re-root the display at the first node calling this function
+	Depth            int      // truncate the display below this depth, 0 for no limit
+	CollapseExternal bool     // fold runs of consecutive external calls into one node
+}
+
+// GetCallTree extracts call tree from a previously generated ctree YAML file
+func GetCallTree(conf *config.Config, req request.GenerateRequest, format string, opts CallTreeOptions) (string, error) {
+	ctree, err := loadCTree(req.SourcePath)
+	if err != nil {
+		return "", err
+	}
+
+	// Keep only entry points of the requested kinds
+	if len(opts.EntryKinds) > 0 {
+		ctree.CallTree = filterEntryPointsByKind(ctree.CallTree, opts.EntryKinds)
+	}
+
+	// Narrow the display to a single entry point, then to the subtree of a function
+	if opts.Entry != "" {
+		entry, err := selectEntryPoint(ctree.CallTree, opts.Entry)
+		if err != nil {
+			return "", err
+		}
+		ctree.CallTree = []model.CallTreeNode{entry}
+	}
+	if opts.Root != "" {
+		root, ok := findSubtreeRoot(ctree.CallTree, opts.Root)
+		if !ok {
+			return "", fmt.Errorf("function not found in call tree: %s", opts.Root)
+		}
+		// How the parent called it is no longer shown
+		root.CallKind, root.CallSites = "", nil
+		ctree.CallTree = []model.CallTreeNode{root}
+	}
+
+	// Drop callees of the hidden origins; builtins and conversions are noise unless asked for
+	if len(opts.HideOrigins) > 0 {
+		ctree.CallTree = hideNodesByOrigin(ctree.CallTree, opts.HideOrigins)
+	}
+	if !opts.ShowBuiltins {
+		ctree.CallTree = hideNodesByKind(ctree.CallTree, []string{"builtin", "conversion"})
+	}
+
+	// View-time shaping of what is left
+	if opts.CollapseExternal {
+		ctree.CallTree = collapseExternalRuns(ctree.CallTree)
+	}
+	if opts.Depth > 0 {
+		ctree.CallTree = truncateDepth(ctree.CallTree, opts.Depth)
 	}
 
 	// Extract call tree based on format
 	switch format {
 	case "text", "tree":
 		// Return indented tree visualization
-		return formatCallTreeAsText(ctree.CallTree, expandSignature), nil
+		if opts.Root != "" {
+			return formatSubtreeAsText(ctree.CallTree, opts.ExpandSignature), nil
+		}
+		return formatCallTreeAsText(ctree.CallTree, opts.ExpandSignature), nil
 	case "yaml", "":
 		// Return call tree nodes as YAML
 		if len(ctree.CallTree) == 0 {
@@ -143,63 +754,360 @@ func GetCallTree(conf *config.Config, req request.GenerateRequest, format string
 	}
 }
 
+// filterEntryPointsByKind keeps the entry point nodes whose kind is one of kinds
+func filterEntryPointsByKind(nodes []model.CallTreeNode, kinds []string) []model.CallTreeNode {
+	var filtered []model.CallTreeNode
+	for _, node := range nodes {
+		for _, kind := range kinds {
+			if node.Kind == kind {
+				filtered = append(filtered, node)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// selectEntryPoint returns the entry point numbered entry, counting from 1, or the first
+// entry point whose function matches entry by name
+func selectEntryPoint(nodes []model.CallTreeNode, entry string) (model.CallTreeNode, error) {
+	if n, err := strconv.Atoi(entry); err == nil {
+		if n < 1 || n > len(nodes) {
+			return model.CallTreeNode{}, fmt.Errorf("entry point %d out of range (1-%d)", n, len(nodes))
+		}
+		return nodes[n-1], nil
+	}
+	for _, node := range nodes {
+		if matchCallTreeNode(entry, node) {
+			return node, nil
+		}
+	}
+	return model.CallTreeNode{}, fmt.Errorf("entry point not found: %s", entry)
+}
+
+// findSubtreeRoot returns the shallowest node matching name across all trees. A node that
+// was expanded is preferred over one cut short as recursive or already visited.
+func findSubtreeRoot(nodes []model.CallTreeNode, name string) (model.CallTreeNode, bool) {
+	var first *model.CallTreeNode
+	queue := append([]model.CallTreeNode(nil), nodes...)
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if matchCallTreeNode(name, node) {
+			if len(node.Children) > 0 {
+				return node, true
+			}
+			if first == nil {
+				first = &node
+			}
+		}
+		queue = append(queue, node.Children...)
+	}
+	if first == nil {
+		return model.CallTreeNode{}, false
+	}
+	return *first, true
+}
+
+// matchCallTreeNode reports whether query names the function of a node. Analyzed functions
+// match like matchQualifiedName, also as Receiver.Name for methods; external callees match
+// their import path qualified name, any suffix of it after a slash as in filepath.Join, or
+// the name declared in their package.
+func matchCallTreeNode(query string, node model.CallTreeNode) bool {
+	if isPlaceholderNode(node) {
+		if query == node.Name || strings.HasSuffix(node.Name, "/"+query) {
+			return true
+		}
+		if node.PackagePath == "" || !strings.HasPrefix(node.Name, node.PackagePath+".") {
+			return false
+		}
+		return matchQualifiedName(query, strings.TrimPrefix(node.Name, node.PackagePath+"."), node.Package, node.PackagePath)
+	}
+	if matchQualifiedName(query, node.Name, node.Package, node.PackagePath) {
+		return true
+	}
+	return node.Receiver != "" && matchQualifiedName(query, node.Receiver+"."+node.Name, node.Package, node.PackagePath)
+}
+
+// collapseExternalRuns folds every run of two or more consecutive external sibling calls
+// into a single node listing them, at any depth
+func collapseExternalRuns(nodes []model.CallTreeNode) []model.CallTreeNode {
+	var collapse func(children []model.CallTreeNode) []model.CallTreeNode
+	collapse = func(children []model.CallTreeNode) []model.CallTreeNode {
+		var result []model.CallTreeNode
+		for i := 0; i < len(children); {
+			j := i
+			for j < len(children) && isPlaceholderNode(children[j]) && len(children[j].Children) == 0 {
+				j++
+			}
+			if j-i >= 2 {
+				result = append(result, newCollapsedNode(children[i:j]))
+				i = j
+				continue
+			}
+			if j == i {
+				j++
+			}
+			for _, child := range children[i:j] {
+				child.Children = collapse(child.Children)
+				result = append(result, child)
+			}
+			i = j
+		}
+		return result
+	}
+
+	collapsed := make([]model.CallTreeNode, len(nodes))
+	for i, node := range nodes {
+		node.Children = collapse(node.Children)
+		collapsed[i] = node
+	}
+	return collapsed
+}
+
+// newCollapsedNode returns the node standing for a run of external calls. It keeps the
+// origin the calls share, if any.
+func newCollapsedNode(run []model.CallTreeNode) model.CallTreeNode {
+	node := model.CallTreeNode{
+		Kind:   "external",
+		Origin: run[0].Origin,
+	}
+	for _, call := range run {
+		node.Collapsed = append(node.Collapsed, call.Title)
+		if call.Origin != node.Origin {
+			node.Origin = ""
+		}
+	}
+
+	const shown = 3
+	titles := node.Collapsed
+	if len(titles) > shown {
+		titles = append(append([]string(nil), titles[:shown]...), "…")
+	}
+	node.Title = fmt.Sprintf("%d external calls: %s", len(run), strings.Join(titles, ", "))
+	return node
+}
+
+// truncateDepth drops the children of nodes depth levels below each root, recording how
+// many descendants were hidden
+func truncateDepth(nodes []model.CallTreeNode, depth int) []model.CallTreeNode {
+	var truncate func(node model.CallTreeNode, level int) model.CallTreeNode
+	truncate = func(node model.CallTreeNode, level int) model.CallTreeNode {
+		if level >= depth {
+			node.Truncated += countDescendants(node)
+			node.Children = nil
+			return node
+		}
+		children := make([]model.CallTreeNode, len(node.Children))
+		for i, child := range node.Children {
+			children[i] = truncate(child, level+1)
+		}
+		node.Children = children
+		return node
+	}
+
+	truncated := make([]model.CallTreeNode, len(nodes))
+	for i, node := range nodes {
+		truncated[i] = truncate(node, 0)
+	}
+	return truncated
+}
+
+// countDescendants returns the number of nodes below node
+func countDescendants(node model.CallTreeNode) int {
+	count := 0
+	for _, child := range node.Children {
+		count += 1 + countDescendants(child)
+	}
+	return count
+}
+
+// hideNodesByOrigin removes unanalyzed callees whose origin is one of origins at any depth.
+// "third-party" is accepted as an alias of "third_party".
+func hideNodesByOrigin(nodes []model.CallTreeNode, origins []string) []model.CallTreeNode {
+	hidden := make(map[string]bool)
+	for _, origin := range origins {
+		hidden[strings.ReplaceAll(origin, "-", "_")] = true
+	}
+	return filterNodes(nodes, func(node model.CallTreeNode) bool {
+		return node.File == "" && hidden[node.Origin]
+	})
+}
+
+// hideNodesByKind removes callees whose kind is one of kinds at any depth
+func hideNodesByKind(nodes []model.CallTreeNode, kinds []string) []model.CallTreeNode {
+	return filterNodes(nodes, func(node model.CallTreeNode) bool {
+		for _, kind := range kinds {
+			if node.Kind == kind {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// filterNodes removes the child nodes for which hide returns true, recursively.
+// Entry point nodes themselves are always kept.
+func filterNodes(nodes []model.CallTreeNode, hide func(node model.CallTreeNode) bool) []model.CallTreeNode {
+	var filter func(nodes []model.CallTreeNode) []model.CallTreeNode
+	filter = func(nodes []model.CallTreeNode) []model.CallTreeNode {
+		var kept []model.CallTreeNode
+		for _, node := range nodes {
+			if hide(node) {
+				continue
+			}
+			node.Children = filter(node.Children)
+			kept = append(kept, node)
+		}
+		return kept
+	}
+
+	filtered := make([]model.CallTreeNode, len(nodes))
+	for i, node := range nodes {
+		node.Children = filter(node.Children)
+		filtered[i] = node
+	}
+	return filtered
+}
+
+// isPlaceholderNode reports whether a node stands for a callee that was not analyzed
+func isPlaceholderNode(node model.CallTreeNode) bool {
+	return node.Kind == "external" || node.Kind == "builtin" || node.Kind == "conversion"
+}
+
+// formatOriginTag returns the colored tag telling where an external node comes from
+func formatOriginTag(node model.CallTreeNode) string {
+	if node.Kind == "conversion" {
+		return colorMagenta + "[conversion]" + colorReset
+	}
+	switch node.Origin {
+	case "internal":
+		return colorGreen + "[internal]" + colorReset
+	case "stdlib":
+		return colorBlue + "[stdlib]" + colorReset
+	case "third_party":
+		return colorYellow + "[third-party]" + colorReset
+	case "builtin":
+		return colorGray + "[builtin]" + colorReset
+	default:
+		return colorGray + "[external]" + colorReset
+	}
+}
+
 // formatCallTreeAsText formats call tree nodes as indented text with colors
 func formatCallTreeAsText(nodes []model.CallTreeNode, expandSignature bool) string {
 	if len(nodes) == 0 {
 		return "No call tree available\n"
 	}
+	return formatTreesAsText(nodes, expandSignature, "Call Tree", "", func(i int, node model.CallTreeNode) string {
+		if node.Kind != "" && node.Kind != "entrypoint" {
+			return fmt.Sprintf("Entry Point %d (%s): ", i+1, node.Kind)
+		}
+		return fmt.Sprintf("Entry Point %d: ", i+1)
+	})
+}
+
+// formatSubtreeAsText formats call tree nodes re-rooted at a function as indented text with colors
+func formatSubtreeAsText(nodes []model.CallTreeNode, expandSignature bool) string {
+	return formatTreesAsText(nodes, expandSignature, "Call Tree", "", func(i int, node model.CallTreeNode) string {
+		return "Root: "
+	})
+}
+
+// formatCallerTreeAsText formats caller tree nodes, whose children are the callers of a node,
+// as indented text with colors
+func formatCallerTreeAsText(nodes []model.CallTreeNode, expandSignature bool) string {
+	if len(nodes) == 0 {
+		return "No caller tree available\n"
+	}
+	return formatTreesAsText(nodes, expandSignature, "Caller Tree", "(no callers)", func(i int, node model.CallTreeNode) string {
+		return "Callers of: "
+	})
+}
 
+// formatTreesAsText writes a heading and every tree introduced by its label; leafNote is
+// written under a root without children
+func formatTreesAsText(nodes []model.CallTreeNode, expandSignature bool, heading string, leafNote string, label func(i int, node model.CallTreeNode) string) string {
 	var result strings.Builder
-	result.WriteString(colorBold + colorCyan + "Call Tree:\n" + colorReset)
-	result.WriteString(colorCyan + "==========" + colorReset + "\n\n")
+	result.WriteString(colorBold + colorCyan + heading + ":\n" + colorReset)
+	result.WriteString(colorCyan + strings.Repeat("=", len(heading)+1) + colorReset + "\n\n")
 
 	for i, node := range nodes {
 		if i > 0 {
 			result.WriteString("\n")
 		}
-		result.WriteString(colorBold + colorYellow + fmt.Sprintf("Entry Point %d: ", i+1) + colorReset)
-		if expandSignature {
-			// For entry points with expand signature, show function name only
-			funcName := "func "
-			if node.Receiver != "" {
-				funcName += fmt.Sprintf("(%s) ", node.Receiver)
-			}
-			funcName += node.Name
-			result.WriteString(colorGreen + funcName + colorReset)
-			result.WriteString(" " + colorGreen + "[internal]" + colorReset)
-			if node.File != "" {
-				result.WriteString(" " + colorGray + fmt.Sprintf("(%s:%d)", node.File, node.Line) + colorReset)
-			}
-			// Show parameters and returns on separate lines
-			if len(node.Parameters) > 0 {
+		result.WriteString(colorBold + colorYellow + label(i, node) + colorReset)
+		formatRootNode(&result, node, expandSignature)
+		if len(node.Children) == 0 && node.Truncated == 0 && leafNote != "" {
+			result.WriteString(colorGray + "  " + leafNote + colorReset + "\n")
+		}
+		formatNodeRecursive(&result, node, "", true, expandSignature)
+	}
+
+	return result.String()
+}
+
+// formatRootNode writes the first line of a tree, followed by the signature lines
+// when expandSignature is set
+func formatRootNode(result *strings.Builder, node model.CallTreeNode, expandSignature bool) {
+	tag := colorGreen + "[internal]" + colorReset
+	if isPlaceholderNode(node) {
+		tag = formatOriginTag(node)
+	}
+
+	if expandSignature {
+		// For root nodes with expand signature, show function name only
+		funcName := "func "
+		if node.Receiver != "" {
+			funcName += fmt.Sprintf("(%s) ", node.Receiver)
+		}
+		funcName += node.Name
+		result.WriteString(colorGreen + funcName + colorReset)
+		result.WriteString(" " + tag)
+		if node.File != "" {
+			result.WriteString(" " + colorGray + fmt.Sprintf("(%s:%d)", node.File, node.Line) + colorReset)
+		}
+		// Show type parameters, parameters and returns on separate lines
+		if len(node.TypeParams) > 0 {
+			result.WriteString("\n")
+			result.WriteString(colorGray + "  Type Parameters:" + colorReset)
+			for _, typeParam := range node.TypeParams {
 				result.WriteString("\n")
-				result.WriteString(colorGray + "  Parameters:" + colorReset)
-				for _, param := range node.Parameters {
-					result.WriteString("\n")
-					result.WriteString(colorGray + "    - " + colorReset)
-					result.WriteString(colorWhite + param.Name + ": " + colorMagenta + param.Type + colorReset)
-				}
+				result.WriteString(colorGray + "    - " + colorReset)
+				result.WriteString(colorWhite + typeParam.Name + ": " + colorMagenta + typeParam.Type + colorReset)
 			}
-			if len(node.ReturnTypes) > 0 {
+		}
+		if len(node.Parameters) > 0 {
+			result.WriteString("\n")
+			result.WriteString(colorGray + "  Parameters:" + colorReset)
+			for _, param := range node.Parameters {
 				result.WriteString("\n")
-				result.WriteString(colorGray + "  Returns:" + colorReset)
-				for _, returnType := range node.ReturnTypes {
-					result.WriteString("\n")
-					result.WriteString(colorGray + "    - " + colorMagenta + returnType + colorReset)
-				}
+				result.WriteString(colorGray + "    - " + colorReset)
+				result.WriteString(colorWhite + param.Name + ": " + colorMagenta + param.Type + colorReset)
 			}
-		} else {
-			result.WriteString(colorGreen + node.Title + colorReset)
-			result.WriteString(" " + colorGreen + "[internal]" + colorReset)
-			if node.File != "" {
-				result.WriteString(" " + colorGray + fmt.Sprintf("(%s:%d)", node.File, node.Line) + colorReset)
+		}
+		if len(node.ReturnTypes) > 0 {
+			result.WriteString("\n")
+			result.WriteString(colorGray + "  Returns:" + colorReset)
+			for _, returnType := range node.ReturnTypes {
+				result.WriteString("\n")
+				result.WriteString(colorGray + "    - " + colorMagenta + returnType + colorReset)
 			}
 		}
-		result.WriteString("\n")
-		formatNodeRecursive(&result, node, "", true, expandSignature)
+	} else {
+		result.WriteString(colorGreen + node.Title + colorReset)
+		result.WriteString(" " + tag)
+		if node.File != "" {
+			result.WriteString(" " + colorGray + fmt.Sprintf("(%s:%d)", node.File, node.Line) + colorReset)
+		}
 	}
-
-	return result.String()
+	if node.Panics {
+		result.WriteString(colorRed + " [panics]" + colorReset)
+	}
+	if node.IsEntry {
+		result.WriteString(colorBold + colorYellow + " [entry]" + colorReset)
+	}
+	result.WriteString("\n")
 }
 
 // formatNodeRecursive recursively formats a call tree node with indentation
@@ -211,6 +1119,7 @@ func formatNodeRecursive(result *strings.Builder, node model.CallTreeNode, prefi
 			childIsLast := i == len(node.Children)-1
 			formatNodeRecursive(result, child, "  ", childIsLast, expandSignature)
 		}
+		formatTruncatedMarker(result, node, "  ")
 		return
 	}
 
@@ -225,13 +1134,13 @@ func formatNodeRecursive(result *strings.Builder, node model.CallTreeNode, prefi
 
 	// Node title with color based on kind
 	titleColor := colorWhite
-	if node.Kind == "external" {
+	if isPlaceholderNode(node) {
 		titleColor = colorGray
-	} else if node.Kind == "function" || node.Kind == "method" {
+	} else if node.Kind == "function" || node.Kind == "method" || node.Kind == "closure" {
 		titleColor = colorBrightCyan
 	}
 
-	if expandSignature && (node.Kind == "function" || node.Kind == "method") {
+	if expandSignature && (node.Kind == "function" || node.Kind == "method" || node.Kind == "closure") {
 		// Show expanded signature with parameters and return values
 		// formatExpandedTitle handles tag and location display
 		formatExpandedTitle(result, node, prefix+getChildPrefix(isLast))
@@ -239,21 +1148,23 @@ func formatNodeRecursive(result *strings.Builder, node model.CallTreeNode, prefi
 		// Show function name
 		result.WriteString(titleColor + node.Title + colorReset)
 
-		// Show [internal] or [external] tag after function name
-		if node.Kind == "external" {
-			result.WriteString(" " + colorGray + "[external]" + colorReset)
+		// Show [internal] or the origin of an external node after function name
+		if isPlaceholderNode(node) || node.Origin == "third_party" {
+			result.WriteString(" " + formatOriginTag(node))
 		} else if node.File != "" {
 			result.WriteString(" " + colorGreen + "[internal]" + colorReset)
 		}
 
 		// Location info or package info after tag
-		if node.Kind == "external" {
+		if isPlaceholderNode(node) {
 			// For external functions, show full package path if available
-			if node.PackagePath != "" {
+			if node.PackagePath != "" && node.ModuleVersion != "" {
+				result.WriteString(" " + colorGray + fmt.Sprintf("(%s@%s)", node.PackagePath, node.ModuleVersion) + colorReset)
+			} else if node.PackagePath != "" {
 				result.WriteString(" " + colorGray + fmt.Sprintf("(%s)", node.PackagePath) + colorReset)
 			} else if node.Package != "" {
 				result.WriteString(" " + colorGray + fmt.Sprintf("(%s)", node.Package) + colorReset)
-			} else {
+			} else if node.Kind == "external" {
 				// Fallback: extract package name from function name
 				packageName := extractPackageName(node.Name)
 				if packageName != "" {
@@ -266,10 +1177,47 @@ func formatNodeRecursive(result *strings.Builder, node model.CallTreeNode, prefi
 		}
 	}
 
+	// Call site in the parent function
+	if len(node.CallSites) > 0 {
+		result.WriteString(" " + colorGray + fmt.Sprintf("@%s:%d", node.CallSites[0].File, node.CallSites[0].Line) + colorReset)
+		if len(node.CallSites) > 1 {
+			result.WriteString(colorGray + fmt.Sprintf(" x%d", len(node.CallSites)) + colorReset)
+		}
+	}
+
 	// Special markers
+	if node.Panics {
+		result.WriteString(colorRed + " [panics]" + colorReset)
+	}
+	if node.ModuleBoundary {
+		result.WriteString(colorBold + colorYellow + fmt.Sprintf(" [module: %s@%s]", node.Module, node.ModuleVersion) + colorReset)
+	}
+	if node.Constraint != "" {
+		result.WriteString(colorCyan + fmt.Sprintf(" [build: %s]", node.Constraint) + colorReset)
+	}
 	if node.IsRecursive {
 		result.WriteString(colorYellow + " [recursive]" + colorReset)
 	}
+	if node.IsEntry {
+		result.WriteString(colorBold + colorYellow + " [entry]" + colorReset)
+	}
+	if node.IsDynamic {
+		result.WriteString(colorMagenta + " [dynamic]" + colorReset)
+	}
+	if node.IsField {
+		result.WriteString(colorMagenta + " [field]" + colorReset)
+	}
+	if node.Via != "" {
+		result.WriteString(colorCyan + fmt.Sprintf(" [via %s]", node.Via) + colorReset)
+	}
+	switch node.CallKind {
+	case "go":
+		result.WriteString(colorRed + " [go]" + colorReset)
+	case "defer":
+		result.WriteString(colorBlue + " [defer]" + colorReset)
+	case "ref":
+		result.WriteString(colorGray + " [ref]" + colorReset)
+	}
 	result.WriteString("\n")
 
 	// Print children
@@ -283,6 +1231,17 @@ func formatNodeRecursive(result *strings.Builder, node model.CallTreeNode, prefi
 		}
 		formatNodeRecursive(result, child, childPrefix, childIsLast, expandSignature)
 	}
+	formatTruncatedMarker(result, node, prefix+getChildPrefix(isLast))
+}
+
+// formatTruncatedMarker writes the last child line of a node cut off by a depth limit
+func formatTruncatedMarker(result *strings.Builder, node model.CallTreeNode, prefix string) {
+	if node.Truncated == 0 {
+		return
+	}
+	result.WriteString(colorGray + prefix + colorReset)
+	result.WriteString(colorBrightBlue + "└─" + colorReset)
+	result.WriteString(" " + colorGray + fmt.Sprintf("… (%d more)", node.Truncated) + colorReset + "\n")
 }
 
 // getChildPrefix returns the prefix for child nodes
@@ -305,9 +1264,9 @@ func formatExpandedTitle(result *strings.Builder, node model.CallTreeNode, child
 	funcName += node.Name
 	result.WriteString(titleColor + funcName + colorReset)
 
-	// Show [internal] or [external] tag after function name
+	// Show [internal] or the origin of an external node after function name
 	if node.Kind == "external" {
-		result.WriteString(" " + colorGray + "[external]" + colorReset)
+		result.WriteString(" " + formatOriginTag(node))
 		// For external functions, show full package path if available
 		if node.PackagePath != "" {
 			result.WriteString(" " + colorGray + fmt.Sprintf("(%s)", node.PackagePath) + colorReset)
@@ -321,11 +1280,27 @@ func formatExpandedTitle(result *strings.Builder, node model.CallTreeNode, child
 			}
 		}
 	} else if node.File != "" {
-		result.WriteString(" " + colorGreen + "[internal]" + colorReset)
+		if node.Origin == "third_party" {
+			// Function followed into dependency source
+			result.WriteString(" " + formatOriginTag(node))
+		} else {
+			result.WriteString(" " + colorGreen + "[internal]" + colorReset)
+		}
 		// For internal functions, show file path
 		result.WriteString(" " + colorGray + fmt.Sprintf("(%s:%d)", node.File, node.Line) + colorReset)
 	}
 
+	// Type parameters on separate lines
+	if len(node.TypeParams) > 0 {
+		result.WriteString("\n")
+		result.WriteString(colorGray + childPrefix + "│  Type Parameters:" + colorReset)
+		for _, typeParam := range node.TypeParams {
+			result.WriteString("\n")
+			result.WriteString(colorGray + childPrefix + "│    - " + colorReset)
+			result.WriteString(colorWhite + typeParam.Name + ": " + colorMagenta + typeParam.Type + colorReset)
+		}
+	}
+
 	// Parameters on separate lines
 	if len(node.Parameters) > 0 {
 		result.WriteString("\n")
@@ -0,0 +1,335 @@
+package golang
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ryo-arima/ctree/pkg/entity/model"
+	"github.com/ryo-arima/ctree/pkg/entity/request"
+)
+
+func TestSortFunctions(t *testing.T) {
+	functions := []model.FunctionSummary{
+		{Key: "p.b", Name: "b", FanIn: 1, FanOut: 3, Lines: 10},
+		{Key: "p.a", Name: "a", FanIn: 2, FanOut: 1, Lines: 10},
+		{Key: "q.a", Name: "a", FanIn: 2, FanOut: 0, Lines: 4},
+		{Key: "p.c", Name: "c", FanIn: 0, FanOut: 3, Lines: 20},
+	}
+
+	tests := []struct {
+		by      string
+		want    []string
+		wantErr bool
+	}{
+		{by: "", want: []string{"p.a", "q.a", "p.b", "p.c"}},
+		{by: "name", want: []string{"p.a", "q.a", "p.b", "p.c"}},
+		{by: "fan-in", want: []string{"p.a", "q.a", "p.b", "p.c"}},
+		{by: "fan-out", want: []string{"p.b", "p.c", "p.a", "q.a"}},
+		{by: "lines", want: []string{"p.c", "p.a", "p.b", "q.a"}},
+		{by: "size", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.by, func(t *testing.T) {
+			sorted := append([]model.FunctionSummary(nil), functions...)
+			err := sortFunctions(sorted, tt.by)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("sortFunctions(%q) succeeded, want an error", tt.by)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sortFunctions(%q) failed: %v", tt.by, err)
+			}
+			var keys []string
+			for _, fn := range sorted {
+				keys = append(keys, fn.Key)
+			}
+			if !reflect.DeepEqual(keys, tt.want) {
+				t.Errorf("order = %v, want %v", keys, tt.want)
+			}
+		})
+	}
+}
+
+func TestListFunctionsFilters(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.22\n",
+		"main.go": `package main
+
+import "example.com/app/store"
+
+func main() {
+	store.New().Save()
+	helper()
+}
+
+func helper() {}
+`,
+		"store/store.go": `package store
+
+type Store struct{}
+
+func New() *Store { return &Store{} }
+
+func (s *Store) Save() { s.flush() }
+
+func (s *Store) flush() {}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		opts    FunctionListOptions
+		want    []string
+		wantErr bool
+	}{
+		{name: "all", want: []string{"New", "Save", "flush", "helper", "main"}},
+		{name: "package name", opts: FunctionListOptions{Package: "^store$"}, want: []string{"New", "Save", "flush"}},
+		{name: "import path", opts: FunctionListOptions{Package: "app/store"}, want: []string{"New", "Save", "flush"}},
+		{name: "receiver", opts: FunctionListOptions{Receiver: "Store"}, want: []string{"Save", "flush"}},
+		{name: "name", opts: FunctionListOptions{Name: "^[A-Z]"}, want: []string{"New", "Save"}},
+		{name: "sort by fan-out", opts: FunctionListOptions{Sort: "fan-out"}, want: []string{"main", "Save", "helper", "New", "flush"}},
+		{name: "invalid pattern", opts: FunctionListOptions{Name: "("}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := request.GenerateRequest{SourcePath: dir, Recursive: true, MaxDepth: 10}
+			out, err := ListFunctions(nil, req, "json", tt.opts)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("ListFunctions succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListFunctions failed: %v", err)
+			}
+			var functions []model.FunctionSummary
+			if err := json.Unmarshal([]byte(out), &functions); err != nil {
+				t.Fatalf("failed to parse the function list: %v", err)
+			}
+			var names []string
+			for _, fn := range functions {
+				names = append(names, fn.Name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("functions = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchFunctionName(t *testing.T) {
+	method := model.FunctionSummary{Name: "Generate", Receiver: "goPureProjectGenerateUsecase", Package: "golang", PackagePath: "github.com/ryo-arima/ctree/pkg/usecase/golang"}
+	function := model.FunctionSummary{Name: "main", Package: "main", PackagePath: "github.com/ryo-arima/ctree/cmd"}
+
+	tests := []struct {
+		query string
+		fn    model.FunctionSummary
+		want  bool
+	}{
+		{"Generate", method, true},
+		{"goPureProjectGenerateUsecase.Generate", method, true},
+		{"golang.goPureProjectGenerateUsecase.Generate", method, true},
+		{"github.com/ryo-arima/ctree/pkg/usecase/golang.goPureProjectGenerateUsecase.Generate", method, true},
+		{"golang.Generate", method, true},
+		{"other.Generate", method, false},
+		{"Gen", method, false},
+		{"main.main", function, true},
+		{"github.com/ryo-arima/ctree/cmd.main", function, true},
+		{"cmd.main", function, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := matchFunctionName(tt.query, tt.fn); got != tt.want {
+				t.Errorf("matchFunctionName(%q) = %t, want %t", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+// treeTitles renders call trees as one line per node, indented by depth, with the number
+// of hidden descendants
+func treeTitles(nodes []model.CallTreeNode) []string {
+	var lines []string
+	var walk func(node model.CallTreeNode, indent string)
+	walk = func(node model.CallTreeNode, indent string) {
+		line := indent + node.Title
+		if node.Truncated > 0 {
+			line += fmt.Sprintf(" (+%d)", node.Truncated)
+		}
+		lines = append(lines, line)
+		for _, child := range node.Children {
+			walk(child, indent+"  ")
+		}
+	}
+	for _, node := range nodes {
+		walk(node, "")
+	}
+	return lines
+}
+
+func TestSelectEntryPoint(t *testing.T) {
+	nodes := []model.CallTreeNode{
+		{Title: "main", Name: "main", Package: "main", PackagePath: "example.com/app/cmd"},
+		{Title: "run", Name: "run", Receiver: "Server", Package: "app", PackagePath: "example.com/app"},
+	}
+
+	tests := []struct {
+		entry   string
+		want    string
+		wantErr bool
+	}{
+		{entry: "1", want: "main"},
+		{entry: "2", want: "run"},
+		{entry: "0", wantErr: true},
+		{entry: "3", wantErr: true},
+		{entry: "main", want: "main"},
+		{entry: "example.com/app/cmd.main", want: "main"},
+		{entry: "Server.run", want: "run"},
+		{entry: "app.Server.run", want: "run"},
+		{entry: "serve", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.entry, func(t *testing.T) {
+			node, err := selectEntryPoint(nodes, tt.entry)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("selectEntryPoint(%q) = %s, want an error", tt.entry, node.Title)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("selectEntryPoint(%q) failed: %v", tt.entry, err)
+			}
+			if node.Title != tt.want {
+				t.Errorf("selectEntryPoint(%q) = %s, want %s", tt.entry, node.Title, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindSubtreeRoot(t *testing.T) {
+	nodes := []model.CallTreeNode{{
+		Title: "main", Name: "main", Package: "main", PackagePath: "example.com/app",
+		Children: []model.CallTreeNode{
+			{Title: "save (recursive)", Name: "save", Package: "main", PackagePath: "example.com/app", IsRecursive: true},
+			{Title: "helper", Name: "helper", Package: "main", PackagePath: "example.com/app", Children: []model.CallTreeNode{
+				{Title: "save", Name: "save", Package: "main", PackagePath: "example.com/app", Children: []model.CallTreeNode{
+					{Title: "filepath.Join()", Name: "path/filepath.Join", Package: "filepath", PackagePath: "path/filepath", Kind: "external"},
+				}},
+			}},
+		},
+	}}
+
+	tests := []struct {
+		name  string
+		want  string
+		found bool
+	}{
+		{name: "main", want: "main", found: true},
+		{name: "save", want: "save", found: true}, // the expanded node, not the recursive one
+		{name: "main.helper", want: "helper", found: true},
+		{name: "filepath.Join", want: "filepath.Join()", found: true},
+		{name: "path/filepath.Join", want: "filepath.Join()", found: true},
+		{name: "Join", want: "filepath.Join()", found: true},
+		{name: "missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, found := findSubtreeRoot(nodes, tt.name)
+			if found != tt.found || node.Title != tt.want {
+				t.Errorf("findSubtreeRoot(%q) = %q %t, want %q %t", tt.name, node.Title, found, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+func TestTruncateDepth(t *testing.T) {
+	nodes := []model.CallTreeNode{{
+		Title: "main",
+		Children: []model.CallTreeNode{
+			{Title: "a", Children: []model.CallTreeNode{
+				{Title: "b", Children: []model.CallTreeNode{{Title: "c"}}},
+			}},
+			{Title: "d"},
+		},
+	}}
+
+	tests := []struct {
+		depth int
+		want  []string
+	}{
+		{0, []string{"main (+4)"}},
+		{1, []string{"main", "  a (+2)", "  d"}},
+		{2, []string{"main", "  a", "    b (+1)", "  d"}},
+		{5, []string{"main", "  a", "    b", "      c", "  d"}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("depth=%d", tt.depth), func(t *testing.T) {
+			if got := treeTitles(truncateDepth(nodes, tt.depth)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("truncateDepth:\n got %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+	if got := treeTitles(nodes); len(got) != 5 {
+		t.Errorf("truncateDepth modified its input: %q", got)
+	}
+}
+
+func TestCollapseExternalRuns(t *testing.T) {
+	external := func(title, origin string) model.CallTreeNode {
+		return model.CallTreeNode{Title: title, Kind: "external", Origin: origin}
+	}
+	nodes := []model.CallTreeNode{{
+		Title: "main",
+		Children: []model.CallTreeNode{
+			external("fmt.Println()", "stdlib"),
+			external("os.Exit()", "stdlib"),
+			{Title: "run", Children: []model.CallTreeNode{
+				external("lib.Do()", "third_party"),
+				external("strings.TrimSpace()", "stdlib"),
+				{Title: "helper"},
+				external("log.Print()", "stdlib"),
+			}},
+			external("a()", "stdlib"),
+			external("b()", "stdlib"),
+			external("c()", "stdlib"),
+			external("d()", "stdlib"),
+		},
+	}}
+
+	want := []string{
+		"main",
+		"  2 external calls: fmt.Println(), os.Exit()",
+		"  run",
+		"    2 external calls: lib.Do(), strings.TrimSpace()",
+		"    helper",
+		"    log.Print()",
+		"  4 external calls: a(), b(), c(), …",
+	}
+	collapsed := collapseExternalRuns(nodes)
+	if got := treeTitles(collapsed); !reflect.DeepEqual(got, want) {
+		t.Errorf("collapseExternalRuns:\n got %q\nwant %q", got, want)
+	}
+
+	origins := []string{collapsed[0].Children[0].Origin, collapsed[0].Children[1].Children[0].Origin}
+	if want := []string{"stdlib", ""}; !reflect.DeepEqual(origins, want) {
+		t.Errorf("collapsed origins = %q, want %q", origins, want)
+	}
+}
@@ -2,6 +2,7 @@ package python
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/ryo-arima/ctree/pkg/config"
 	"github.com/ryo-arima/ctree/pkg/entity/request"
@@ -56,7 +57,7 @@ Supports various Python frameworks and pure projects.`,
 			}
 
 			if err != nil {
-				fmt.Printf("Error: %v\n", err)
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				return
 			}
 
@@ -2,63 +2,283 @@ package model
 
 // CallTree represents the entire call tree structure
 type CTree struct {
-	SourceFile            string                 `yaml:"source_file"`
-	Language              string                 `yaml:"language"`
-	Functions             []Function             `yaml:"functions,omitempty"`
-	CallGraph             []CallEdge             `yaml:"call_graph,omitempty"`
-	EntryPoints           []Function             `yaml:"entry_points,omitempty"`
-	CallTree              []CallTreeNode         `yaml:"call_tree,omitempty"`
-	CallTreeVisualization string                 `yaml:"call_tree_visualization,omitempty"`
-	ImportMap             map[string]string      `yaml:"import_map,omitempty"` // package name -> full import path
-	Metadata              map[string]interface{} `yaml:"metadata,omitempty"`
+	SourceFile            string                       `yaml:"source_file"`
+	Language              string                       `yaml:"language"`
+	Functions             []Function                   `yaml:"functions,omitempty"`
+	CallGraph             []CallEdge                   `yaml:"call_graph,omitempty"`
+	EntryPoints           []Function                   `yaml:"entry_points,omitempty"`
+	CallTree              []CallTreeNode               `yaml:"call_tree,omitempty"`
+	CallTreeVisualization string                       `yaml:"call_tree_visualization,omitempty"`
+	FileImports           map[string]map[string]string `yaml:"file_imports,omitempty"` // file -> package name -> full import path
+	Modules               []ModuleSummary              `yaml:"modules,omitempty"`      // Go modules analyzed together
+	Implements            []Implementation             `yaml:"implements,omitempty"`   // Types satisfying the interfaces of the source
+	Metadata              map[string]interface{}       `yaml:"metadata,omitempty"`
+}
+
+// ModuleSummary represents the share of one module in a multi-module analysis
+type ModuleSummary struct {
+	Path        string `yaml:"path"`
+	Dir         string `yaml:"dir"`
+	Packages    int    `yaml:"packages"`
+	Functions   int    `yaml:"functions"`
+	EntryPoints int    `yaml:"entry_points"`
 }
 
 // CallTreeNode represents a node in the hierarchical call tree
 type CallTreeNode struct {
-	Title       string         `yaml:"title"`
-	Name        string         `yaml:"name,omitempty"`
-	Package     string         `yaml:"package,omitempty"`
-	PackagePath string         `yaml:"package_path,omitempty"` // Full import path for external packages
-	File        string         `yaml:"file"`
-	Line        int            `yaml:"line"`
-	Kind        string         `yaml:"kind,omitempty"`
-	Receiver    string         `yaml:"receiver,omitempty"`
-	Signature   string         `yaml:"signature,omitempty"`
-	Parameters  []Parameter    `yaml:"parameters,omitempty"`
-	ReturnTypes []string       `yaml:"return_types,omitempty"`
-	Children    []CallTreeNode `yaml:"children,omitempty"`
-	IsRecursive bool           `yaml:"is_recursive,omitempty"`
+	Title          string         `yaml:"title"`
+	Name           string         `yaml:"name,omitempty"`
+	Package        string         `yaml:"package,omitempty"`
+	PackagePath    string         `yaml:"package_path,omitempty"` // Full import path for external packages
+	File           string         `yaml:"file"`
+	Line           int            `yaml:"line"`
+	Kind           string         `yaml:"kind,omitempty"`
+	Receiver       string         `yaml:"receiver,omitempty"`
+	Constraint     string         `yaml:"constraint,omitempty"` // Build constraint of a platform-specific definition
+	Signature      string         `yaml:"signature,omitempty"`
+	TypeParams     []Parameter    `yaml:"type_params,omitempty"`
+	Parameters     []Parameter    `yaml:"parameters,omitempty"`
+	ReturnTypes    []string       `yaml:"return_types,omitempty"`
+	Children       []CallTreeNode `yaml:"children,omitempty"`
+	IsRecursive    bool           `yaml:"is_recursive,omitempty"`
+	IsDynamic      bool           `yaml:"is_dynamic,omitempty"` // Reached through an interface method call
+	IsField        bool           `yaml:"is_field,omitempty"`   // Called through a function-typed struct field
+	Panics         bool           `yaml:"panics,omitempty"`     // Calls the panic builtin
+	Via            string         `yaml:"via,omitempty"`        // Embedded fields a promoted method is reached through
+	CallKind       string         `yaml:"call_kind,omitempty"`  // call, go, defer or ref
+	CallSites      []CallSite     `yaml:"call_sites,omitempty"` // Where the parent calls this node
+	Origin         string         `yaml:"origin,omitempty"`     // internal, stdlib, third_party or builtin
+	Module         string         `yaml:"module,omitempty"`     // Module providing a third-party package
+	ModuleVersion  string         `yaml:"module_version,omitempty"`
+	ModuleBoundary bool           `yaml:"module_boundary,omitempty"` // First node inside a dependency module
+	IsEntry        bool           `yaml:"is_entry,omitempty"`        // Entry point reached in a caller tree
+	Truncated      int            `yaml:"truncated,omitempty"`       // Descendants hidden by a depth limit (distinct callers in a caller tree)
+	Collapsed      []string       `yaml:"collapsed,omitempty"`       // Titles of the external calls folded into this node
 }
 
 // Function represents a function or method in the source code
 type Function struct {
-	Name        string      `yaml:"name"`
-	File        string      `yaml:"file"`
-	Line        int         `yaml:"line"`
-	Kind        string      `yaml:"kind"`            // function, method, class, etc.
-	Signature   string      `yaml:"signature"`       // function signature
-	Class       string      `yaml:"class,omitempty"` // class name if it's a method
-	Namespace   string      `yaml:"namespace,omitempty"`
-	Access      string      `yaml:"access,omitempty"` // public, private, protected
-	CallsTo     []string    `yaml:"calls_to,omitempty"`
-	Package     string      `yaml:"package,omitempty"`      // Go package name
-	Receiver    string      `yaml:"receiver,omitempty"`     // Go method receiver
-	Parameters  []Parameter `yaml:"parameters,omitempty"`   // Function parameters
-	ReturnTypes []string    `yaml:"return_types,omitempty"` // Return types
+	Name          string      `yaml:"name"`
+	File          string      `yaml:"file"`
+	Line          int         `yaml:"line"`
+	EndLine       int         `yaml:"end_line,omitempty"`
+	Kind          string      `yaml:"kind"`            // function, method, class, etc.
+	Signature     string      `yaml:"signature"`       // function signature
+	Class         string      `yaml:"class,omitempty"` // class name if it's a method
+	Namespace     string      `yaml:"namespace,omitempty"`
+	Access        string      `yaml:"access,omitempty"` // public, private, protected
+	CallsTo       []string    `yaml:"calls_to,omitempty"`
+	DynamicCalls  []string    `yaml:"dynamic_calls,omitempty"` // Callees reached through interface method calls
+	FieldCalls    []string    `yaml:"field_calls,omitempty"`   // Callees called through function-typed struct fields
+	Package       string      `yaml:"package,omitempty"`       // Go package name
+	PackagePath   string      `yaml:"package_path,omitempty"`  // Go package import path
+	Receiver      string      `yaml:"receiver,omitempty"`      // Go method receiver
+	Constraint    string      `yaml:"constraint,omitempty"`    // Go build constraint of the declaring file
+	Module        string      `yaml:"module,omitempty"`        // Go module providing the function
+	ModuleVersion string      `yaml:"module_version,omitempty"`
+	TypeParams    []Parameter `yaml:"type_params,omitempty"`  // Generic type parameters (type holds the constraint)
+	Parameters    []Parameter `yaml:"parameters,omitempty"`   // Function parameters
+	ReturnTypes   []string    `yaml:"return_types,omitempty"` // Return types
+	Doc           string      `yaml:"doc,omitempty"`          // Doc comment
+}
+
+// FunctionSummary represents a function with its size and its fan-in and fan-out
+// in the call graph of the analyzed source
+type FunctionSummary struct {
+	Key         string `json:"key" yaml:"key"`
+	Name        string `json:"name" yaml:"name"`
+	Kind        string `json:"kind" yaml:"kind"`
+	Package     string `json:"package,omitempty" yaml:"package,omitempty"`
+	PackagePath string `json:"package_path,omitempty" yaml:"package_path,omitempty"`
+	Receiver    string `json:"receiver,omitempty" yaml:"receiver,omitempty"`
+	Signature   string `json:"signature" yaml:"signature"`
+	File        string `json:"file" yaml:"file"`
+	Line        int    `json:"line" yaml:"line"`
+	Lines       int    `json:"lines,omitempty" yaml:"lines,omitempty"` // Source lines from declaration to closing brace
+	Exported    bool   `json:"exported" yaml:"exported"`
+	FanIn       int    `json:"fan_in" yaml:"fan_in"`   // Distinct callers
+	FanOut      int    `json:"fan_out" yaml:"fan_out"` // Distinct callees
+}
+
+// FunctionDetail represents a function with its direct callers and callees
+type FunctionDetail struct {
+	FunctionSummary `yaml:",inline"`
+	EndLine         int            `json:"end_line,omitempty" yaml:"end_line,omitempty"`
+	Doc             string         `json:"doc,omitempty" yaml:"doc,omitempty"`
+	Callers         []FunctionCall `json:"callers,omitempty" yaml:"callers,omitempty"`
+	Callees         []FunctionCall `json:"callees,omitempty" yaml:"callees,omitempty"`
+	Reachable       bool           `json:"reachable" yaml:"reachable"`                               // Called directly or indirectly from an entry point
+	ReachableFrom   []string       `json:"reachable_from,omitempty" yaml:"reachable_from,omitempty"` // Entry points reaching the function
+}
+
+// FunctionCall represents a direct call between a function and one of its callers or callees
+type FunctionCall struct {
+	Function string     `json:"function" yaml:"function"`
+	CallKind string     `json:"call_kind,omitempty" yaml:"call_kind,omitempty"`
+	Dynamic  bool       `json:"dynamic,omitempty" yaml:"dynamic,omitempty"`
+	Field    bool       `json:"field,omitempty" yaml:"field,omitempty"`       // Call through a function-typed struct field
+	External bool       `json:"external,omitempty" yaml:"external,omitempty"` // Callee outside the analyzed source
+	Sites    []CallSite `json:"sites,omitempty" yaml:"sites,omitempty"`
 }
 
 // Parameter represents a function parameter
 type Parameter struct {
-	Name string `yaml:"name,omitempty"`
-	Type string `yaml:"type"`
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	Type string `json:"type" yaml:"type"`
+}
+
+// Class represents a type declaration such as a Go struct, interface, alias or named type
+type Class struct {
+	Name        string        `json:"name" yaml:"name"`
+	Kind        string        `json:"kind" yaml:"kind"` // struct, interface, alias or named
+	Package     string        `json:"package,omitempty" yaml:"package,omitempty"`
+	PackagePath string        `json:"package_path,omitempty" yaml:"package_path,omitempty"`
+	File        string        `json:"file" yaml:"file"`
+	Line        int           `json:"line" yaml:"line"`
+	Doc         string        `json:"doc,omitempty" yaml:"doc,omitempty"`
+	TypeParams  []Parameter   `json:"type_params,omitempty" yaml:"type_params,omitempty"`
+	Underlying  string        `json:"underlying,omitempty" yaml:"underlying,omitempty"` // Type expression of aliases and named types
+	Fields      []Field       `json:"fields,omitempty" yaml:"fields,omitempty"`
+	Embeds      []string      `json:"embeds,omitempty" yaml:"embeds,omitempty"` // Embedded struct fields or interfaces
+	Methods     []ClassMethod `json:"methods,omitempty" yaml:"methods,omitempty"`
+}
+
+// Field represents a struct field
+type Field struct {
+	Name     string `json:"name" yaml:"name"`
+	Type     string `json:"type" yaml:"type"`
+	Tag      string `json:"tag,omitempty" yaml:"tag,omitempty"`
+	Embedded bool   `json:"embedded,omitempty" yaml:"embedded,omitempty"`
+}
+
+// ClassMethod represents a method in the method set of a type
+type ClassMethod struct {
+	Name      string `json:"name" yaml:"name"`
+	Signature string `json:"signature" yaml:"signature"`
+	Pointer   bool   `json:"pointer,omitempty" yaml:"pointer,omitempty"` // Declared with a pointer receiver
+	Via       string `json:"via,omitempty" yaml:"via,omitempty"`         // Embedded fields the method is promoted through
+}
+
+// Variable represents a package-level variable or constant
+type Variable struct {
+	Name         string              `json:"name" yaml:"name"`
+	Kind         string              `json:"kind" yaml:"kind"` // var or const
+	Package      string              `json:"package,omitempty" yaml:"package,omitempty"`
+	PackagePath  string              `json:"package_path,omitempty" yaml:"package_path,omitempty"`
+	File         string              `json:"file" yaml:"file"`
+	Line         int                 `json:"line" yaml:"line"`
+	Doc          string              `json:"doc,omitempty" yaml:"doc,omitempty"`
+	Type         string              `json:"type,omitempty" yaml:"type,omitempty"`
+	Value        string              `json:"value,omitempty" yaml:"value,omitempty"`       // Initial value expression
+	Constant     string              `json:"constant,omitempty" yaml:"constant,omitempty"` // Computed value of an iota constant
+	Iota         bool                `json:"iota,omitempty" yaml:"iota,omitempty"`
+	Exported     bool                `json:"exported" yaml:"exported"`
+	ReferencedBy []VariableReference `json:"referenced_by,omitempty" yaml:"referenced_by,omitempty"`
+}
+
+// VariableReference represents a function reading or writing a package-level variable or constant
+type VariableReference struct {
+	Function string `json:"function" yaml:"function"` // Function key, or the variable or constant whose initializer holds the reference
+	Access   string `json:"access" yaml:"access"`     // read or write
+	File     string `json:"file" yaml:"file"`
+	Line     int    `json:"line" yaml:"line"` // First reference of this kind in the function
+}
+
+// ImportReport represents the imports of the analyzed source per file, per package and per import path
+type ImportReport struct {
+	Files    []FileImports    `json:"files" yaml:"files"`
+	Packages []PackageImports `json:"packages" yaml:"packages"`
+	Paths    []ImportSummary  `json:"paths" yaml:"paths"`
+	Origins  map[string]int   `json:"origins" yaml:"origins"` // Distinct import paths per origin
+}
+
+// FileImports represents the import declarations of a file
+type FileImports struct {
+	File        string   `json:"file" yaml:"file"`
+	Package     string   `json:"package" yaml:"package"`
+	PackagePath string   `json:"package_path,omitempty" yaml:"package_path,omitempty"`
+	Imports     []Import `json:"imports,omitempty" yaml:"imports,omitempty"`
+}
+
+// Import represents an import declaration
+type Import struct {
+	Path          string `json:"path" yaml:"path"`
+	Name          string `json:"name,omitempty" yaml:"name,omitempty"` // Explicit package name, _ or .
+	Blank         bool   `json:"blank,omitempty" yaml:"blank,omitempty"`
+	Dot           bool   `json:"dot,omitempty" yaml:"dot,omitempty"`
+	Origin        string `json:"origin,omitempty" yaml:"origin,omitempty"` // internal, stdlib or third_party
+	Module        string `json:"module,omitempty" yaml:"module,omitempty"`
+	ModuleVersion string `json:"module_version,omitempty" yaml:"module_version,omitempty"`
+	Line          int    `json:"line" yaml:"line"`
+}
+
+// PackageImports represents the distinct imports of a package across its files
+type PackageImports struct {
+	Package     string   `json:"package" yaml:"package"`
+	PackagePath string   `json:"package_path,omitempty" yaml:"package_path,omitempty"`
+	Files       int      `json:"files" yaml:"files"`
+	Imports     []string `json:"imports,omitempty" yaml:"imports,omitempty"`
+	Stdlib      int      `json:"stdlib" yaml:"stdlib"`
+	Internal    int      `json:"internal" yaml:"internal"`
+	ThirdParty  int      `json:"third_party" yaml:"third_party"`
+}
+
+// ImportSummary aggregates the import declarations of one import path
+type ImportSummary struct {
+	Path          string   `json:"path" yaml:"path"`
+	Origin        string   `json:"origin,omitempty" yaml:"origin,omitempty"`
+	Module        string   `json:"module,omitempty" yaml:"module,omitempty"`
+	ModuleVersion string   `json:"module_version,omitempty" yaml:"module_version,omitempty"`
+	Files         int      `json:"files" yaml:"files"`       // Importing files
+	Packages      int      `json:"packages" yaml:"packages"` // Importing packages
+	Aliases       []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	Blank         int      `json:"blank,omitempty" yaml:"blank,omitempty"`
+	Dot           int      `json:"dot,omitempty" yaml:"dot,omitempty"`
+}
+
+// Implementation records that a type declared in the analyzed source satisfies an interface
+// declared in the analyzed source
+type Implementation struct {
+	Interface            string `json:"interface" yaml:"interface"`
+	InterfacePackage     string `json:"interface_package" yaml:"interface_package"`
+	InterfacePackagePath string `json:"interface_package_path" yaml:"interface_package_path"`
+	InterfaceFile        string `json:"interface_file" yaml:"interface_file"`
+	InterfaceLine        int    `json:"interface_line" yaml:"interface_line"`
+	Type                 string `json:"type" yaml:"type"`
+	Package              string `json:"package" yaml:"package"`
+	PackagePath          string `json:"package_path" yaml:"package_path"`
+	File                 string `json:"file" yaml:"file"`
+	Line                 int    `json:"line" yaml:"line"`
+	Pointer              bool   `json:"pointer,omitempty" yaml:"pointer,omitempty"` // Only *T implements the interface because of pointer receivers
 }
 
 // CallEdge represents a call relationship between functions
+// File, Line and Column locate the first call site in the caller
 type CallEdge struct {
-	From string `yaml:"from"`
-	To   string `yaml:"to"`
-	File string `yaml:"file"`
-	Line int    `yaml:"line"`
+	From     string     `json:"from" yaml:"from"`
+	To       string     `json:"to" yaml:"to"`
+	File     string     `json:"file" yaml:"file"`
+	Line     int        `json:"line" yaml:"line"`
+	Column   int        `json:"column,omitempty" yaml:"column,omitempty"`
+	Count    int        `json:"count,omitempty" yaml:"count,omitempty"`         // Number of call sites
+	Sites    []CallSite `json:"sites,omitempty" yaml:"sites,omitempty"`         // Every call site when there is more than one
+	Dynamic  bool       `json:"dynamic,omitempty" yaml:"dynamic,omitempty"`     // Interface method dispatch
+	CallKind string     `json:"call_kind,omitempty" yaml:"call_kind,omitempty"` // call, go, defer or ref
+	Via      string     `json:"via,omitempty" yaml:"via,omitempty"`             // Embedded fields a promoted method is reached through
+}
+
+// CallPath represents a chain of calls from one function to another
+type CallPath struct {
+	From string     `json:"from" yaml:"from"`
+	To   string     `json:"to" yaml:"to"`
+	Hops []CallEdge `json:"hops" yaml:"hops"` // One call graph edge per call, in calling order
+}
+
+// CallSite represents the location of a call expression
+type CallSite struct {
+	File   string `json:"file" yaml:"file"`
+	Line   int    `json:"line" yaml:"line"`
+	Column int    `json:"column,omitempty" yaml:"column,omitempty"`
 }
 
 // Tag represents a ctags tag entry
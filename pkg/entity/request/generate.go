@@ -13,6 +13,17 @@ type GenerateRequest struct {
 	ExcludeFiles []string `json:"exclude_files,omitempty" yaml:"exclude_files,omitempty"`
 	IncludeFiles []string `json:"include_files,omitempty" yaml:"include_files,omitempty"`
 	MaxDepth     int      `json:"max_depth,omitempty" yaml:"max_depth,omitempty"`
+	Dispatch     string   `json:"dispatch,omitempty" yaml:"dispatch,omitempty"` // Interface call resolution: static, cha
+	GOOS         string   `json:"goos,omitempty" yaml:"goos,omitempty"`
+	GOARCH       string   `json:"goarch,omitempty" yaml:"goarch,omitempty"`
+	Tags         []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	AllPlatforms bool     `json:"all_platforms,omitempty" yaml:"all_platforms,omitempty"`
+	IncludeTests bool     `json:"include_tests,omitempty" yaml:"include_tests,omitempty"`
+	// EntryPatterns selects additional entry points, e.g. pkg/usecase/golang.*.Generate
+	EntryPatterns   []string `json:"entry_patterns,omitempty" yaml:"entry_patterns,omitempty"`
+	ExportedAsEntry bool     `json:"exported_as_entry,omitempty" yaml:"exported_as_entry,omitempty"`
+	// FollowDeps is how many package levels the call tree descends into dependency source
+	FollowDeps int `json:"follow_deps,omitempty" yaml:"follow_deps,omitempty"`
 }
 
 // Validate validates the generate request
@@ -0,0 +1,419 @@
+package golang
+
+import (
+	"fmt"
+	"go/build"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// GoModule represents a module declared by a go.mod file
+type GoModule struct {
+	Path     string
+	Dir      string
+	Requires map[string]string // module path -> required version
+}
+
+// ModuleReplacement represents the target of a replace directive
+type ModuleReplacement struct {
+	Path    string // replacement module path, or the local directory as written
+	Version string // replacement version, empty for local directories
+	Dir     string // absolute directory of a local replacement
+}
+
+// ModuleInfo represents the modules that own the analyzed source and their dependencies
+type ModuleInfo struct {
+	MainModules []GoModule                   // go.work modules, the enclosing module and every module under the source path
+	Versions    map[string]string            // dependency module path -> version from go.mod, then go.sum
+	Replaces    map[string]ModuleReplacement // replaced module path -> replacement, go.work replaces first
+}
+
+// modDirective represents a single go.mod or go.work directive such as require or use
+type modDirective struct {
+	Verb string
+	Args []string
+}
+
+// LoadModuleInfo reads the go.work and go.mod files that apply to the source path,
+// including the go.mod of every module found below it
+func (r *goPureProjectRepository) LoadModuleInfo(sourcePath string) (*ModuleInfo, error) {
+	absPath, err := filepath.Abs(sourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	if info, err := os.Stat(absPath); err == nil && !info.IsDir() {
+		absPath = filepath.Dir(absPath)
+	}
+
+	info := &ModuleInfo{
+		Versions: make(map[string]string),
+		Replaces: make(map[string]ModuleReplacement),
+	}
+	var moduleDirs []string
+
+	// A go.work file makes every module it uses a main module
+	if workDir := findUp(absPath, "go.work"); workDir != "" {
+		directives, err := readModDirectives(filepath.Join(workDir, "go.work"))
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range directives {
+			switch d.Verb {
+			case "use":
+				if len(d.Args) > 0 {
+					moduleDirs = append(moduleDirs, filepath.Join(workDir, filepath.FromSlash(d.Args[0])))
+				}
+			case "replace":
+				addReplacement(info.Replaces, d.Args, workDir)
+			}
+		}
+	} else if modDir := findUp(absPath, "go.mod"); modDir != "" {
+		moduleDirs = append(moduleDirs, modDir)
+	}
+
+	// Monorepos keep further modules below the source path
+	moduleDirs = append(moduleDirs, findModuleDirs(absPath)...)
+	if len(moduleDirs) == 0 {
+		return nil, fmt.Errorf("no go.mod found for %s", sourcePath)
+	}
+
+	seen := make(map[string]bool)
+	for _, dir := range moduleDirs {
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+
+		module, replaces, err := r.readGoModule(dir)
+		if err != nil {
+			return nil, err
+		}
+		info.MainModules = append(info.MainModules, *module)
+		for path, replacement := range replaces {
+			// go.work replaces and those read first take precedence
+			if _, ok := info.Replaces[path]; !ok {
+				info.Replaces[path] = replacement
+			}
+		}
+	}
+
+	for _, module := range info.MainModules {
+		for path, version := range module.Requires {
+			if _, ok := info.Versions[path]; !ok {
+				info.Versions[path] = version
+			}
+		}
+	}
+	for _, module := range info.MainModules {
+		// go.sum also lists modules that go.mod does not require directly
+		for path, version := range readGoSum(filepath.Join(module.Dir, "go.sum")) {
+			if _, ok := info.Versions[path]; !ok {
+				info.Versions[path] = version
+			}
+		}
+	}
+
+	return info, nil
+}
+
+// MainModule returns the main module that provides a package, or nil
+func (m *ModuleInfo) MainModule(pkgPath string) *GoModule {
+	var found *GoModule
+	for i, module := range m.MainModules {
+		if hasPathPrefix(pkgPath, module.Path) && (found == nil || len(module.Path) > len(found.Path)) {
+			found = &m.MainModules[i]
+		}
+	}
+	return found
+}
+
+// DependencyModule returns the path and version of the required module that provides a package.
+// Replaced modules report the replacement version, which is empty for local directories.
+func (m *ModuleInfo) DependencyModule(pkgPath string) (string, string) {
+	var modulePath string
+	for path := range m.Versions {
+		if hasPathPrefix(pkgPath, path) && len(path) > len(modulePath) {
+			modulePath = path
+		}
+	}
+	for path := range m.Replaces {
+		if hasPathPrefix(pkgPath, path) && len(path) > len(modulePath) {
+			modulePath = path
+		}
+	}
+	if replacement, ok := m.Replaces[modulePath]; ok {
+		return modulePath, replacement.Version
+	}
+	return modulePath, m.Versions[modulePath]
+}
+
+// hasPathPrefix reports whether an import path is prefix or lies below it
+func hasPathPrefix(path, prefix string) bool {
+	return prefix != "" && (path == prefix || strings.HasPrefix(path, prefix+"/"))
+}
+
+// findModuleDirs returns the directories below root that contain a go.mod file,
+// skipping the directories the go tool ignores
+func findModuleDirs(root string) []string {
+	var dirs []string
+	filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() && path != root {
+			name := d.Name()
+			if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "vendor" || name == "testdata" {
+				return filepath.SkipDir
+			}
+		}
+		if !d.IsDir() && d.Name() == "go.mod" {
+			dirs = append(dirs, filepath.Dir(path))
+		}
+		return nil
+	})
+	return dirs
+}
+
+// addReplacement records a replace directive of a go.mod or go.work file in dir.
+// Args have the form "old [version] => new [version]".
+func addReplacement(replaces map[string]ModuleReplacement, args []string, dir string) {
+	arrow := -1
+	for i, arg := range args {
+		if arg == "=>" {
+			arrow = i
+			break
+		}
+	}
+	if arrow < 1 || arrow+1 >= len(args) {
+		return
+	}
+
+	replacement := ModuleReplacement{Path: args[arrow+1]}
+	if arrow+2 < len(args) {
+		replacement.Version = args[arrow+2]
+	}
+	// Local replacements are file paths, which always start with ./, ../ or /
+	if target := replacement.Path; filepath.IsAbs(target) || strings.HasPrefix(target, "./") || strings.HasPrefix(target, "../") {
+		replacement.Dir = filepath.FromSlash(target)
+		if !filepath.IsAbs(replacement.Dir) {
+			replacement.Dir = filepath.Join(dir, replacement.Dir)
+		}
+		replacement.Dir = filepath.Clean(replacement.Dir)
+	}
+	replaces[args[0]] = replacement
+}
+
+// readGoModule reads the module path, requirements and replacements from the go.mod in dir
+func (r *goPureProjectRepository) readGoModule(dir string) (*GoModule, map[string]ModuleReplacement, error) {
+	directives, err := readModDirectives(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	module := &GoModule{
+		Dir:      dir,
+		Requires: make(map[string]string),
+	}
+	replaces := make(map[string]ModuleReplacement)
+	for _, d := range directives {
+		switch d.Verb {
+		case "module":
+			if len(d.Args) > 0 {
+				module.Path = d.Args[0]
+			}
+		case "require":
+			if len(d.Args) >= 2 {
+				module.Requires[d.Args[0]] = d.Args[1]
+			}
+		case "replace":
+			addReplacement(replaces, d.Args, dir)
+		}
+	}
+	return module, replaces, nil
+}
+
+// findUp returns the first directory from dir upwards that contains name, or ""
+func findUp(dir, name string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// readModDirectives reads the directives of a go.mod or go.work file
+func readModDirectives(filePath string) ([]modDirective, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+	return parseModDirectives(data), nil
+}
+
+// parseModDirectives splits go.mod or go.work content into directives,
+// expanding blocks like require ( ... ) into one directive per line
+func parseModDirectives(data []byte) []modDirective {
+	var directives []modDirective
+	var block string
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		for i, field := range fields {
+			fields[i] = strings.Trim(field, "\"`")
+		}
+
+		switch {
+		case block != "" && fields[0] == ")":
+			block = ""
+		case block != "":
+			directives = append(directives, modDirective{Verb: block, Args: fields})
+		case len(fields) == 2 && fields[1] == "(":
+			block = fields[0]
+		default:
+			directives = append(directives, modDirective{Verb: fields[0], Args: fields[1:]})
+		}
+	}
+
+	return directives
+}
+
+// parseModulePath returns the module path declared in go.mod content
+func parseModulePath(data []byte) string {
+	for _, d := range parseModDirectives(data) {
+		if d.Verb == "module" && len(d.Args) > 0 {
+			return d.Args[0]
+		}
+	}
+	return ""
+}
+
+// readGoSum returns the last version listed for each module in a go.sum file.
+// The go command keeps go.sum sorted by semantic version, so this is the highest one.
+func readGoSum(filePath string) map[string]string {
+	versions := make(map[string]string)
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return versions
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		// Entries for the go.mod file alone do not mean the module source is used
+		if strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+		versions[fields[0]] = fields[1]
+	}
+	return versions
+}
+
+// DependencyPackage represents the local source of a package provided by a dependency module
+type DependencyPackage struct {
+	Path      string // import path
+	Dir       string // package source directory
+	Module    string // module path providing the package
+	Version   string // module version
+	ModuleDir string // root directory of the module source
+}
+
+// FindDependencyPackage locates the source of a dependency package in a local replacement,
+// the vendor directory of a main module or the module cache. Nothing is ever downloaded.
+func (r *goPureProjectRepository) FindDependencyPackage(pkgPath string, modules *ModuleInfo) (*DependencyPackage, error) {
+	if modules == nil {
+		return nil, fmt.Errorf("no module information to locate %s", pkgPath)
+	}
+
+	dep := &DependencyPackage{Path: pkgPath}
+	dep.Module, dep.Version = modules.DependencyModule(pkgPath)
+	if dep.Module == "" {
+		return nil, fmt.Errorf("no module provides %s", pkgPath)
+	}
+	subDir := filepath.FromSlash(strings.TrimPrefix(strings.TrimPrefix(pkgPath, dep.Module), "/"))
+
+	// Replacements pointing at a local directory need neither vendor nor module cache
+	replacement, replaced := modules.Replaces[dep.Module]
+	if replaced && replacement.Dir != "" {
+		if !isDir(filepath.Join(replacement.Dir, subDir)) {
+			return nil, fmt.Errorf("source of %s not found in replacement %s", pkgPath, replacement.Path)
+		}
+		dep.ModuleDir = replacement.Dir
+		dep.Dir = filepath.Join(replacement.Dir, subDir)
+		return dep, nil
+	}
+
+	for _, module := range modules.MainModules {
+		vendorDir := filepath.Join(module.Dir, "vendor", filepath.FromSlash(dep.Module))
+		if isDir(filepath.Join(vendorDir, subDir)) {
+			dep.ModuleDir = vendorDir
+			dep.Dir = filepath.Join(vendorDir, subDir)
+			return dep, nil
+		}
+	}
+
+	// The module cache holds replacements under their own module path
+	cachedModule := dep.Module
+	if replaced {
+		cachedModule = replacement.Path
+	}
+	modCache := goModCache()
+	if modCache == "" {
+		return nil, fmt.Errorf("module cache not found for %s", pkgPath)
+	}
+	moduleDir := filepath.Join(modCache, filepath.FromSlash(escapeModulePath(cachedModule))+"@"+escapeModulePath(dep.Version))
+	if !isDir(filepath.Join(moduleDir, subDir)) {
+		return nil, fmt.Errorf("source of %s@%s not found in vendor or module cache", cachedModule, dep.Version)
+	}
+	dep.ModuleDir = moduleDir
+	dep.Dir = filepath.Join(moduleDir, subDir)
+	return dep, nil
+}
+
+// goModCache returns the module cache directory the go command would use
+func goModCache() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		gopath = build.Default.GOPATH
+	}
+	if gopath == "" {
+		return ""
+	}
+	return filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod")
+}
+
+// escapeModulePath applies the module cache case encoding, which replaces every
+// upper-case letter with an exclamation mark followed by its lower-case form
+func escapeModulePath(path string) string {
+	var escaped strings.Builder
+	for _, r := range path {
+		if unicode.IsUpper(r) {
+			escaped.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
+
+// isDir reports whether path exists and is a directory
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
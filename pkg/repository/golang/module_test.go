@@ -0,0 +1,111 @@
+package golang
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeFiles creates files with the given contents under dir
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadModuleInfo(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": `module example.com/app
+
+go 1.22
+
+require github.com/spf13/cobra v1.8.0 // indirect
+
+require (
+	gopkg.in/yaml.v3 v3.0.1
+)
+`,
+		"go.sum": `github.com/spf13/cobra v1.8.0 h1:abc=
+github.com/spf13/cobra v1.8.0/go.mod h1:def=
+github.com/spf13/pflag v1.0.5 h1:ghi=
+github.com/spf13/pflag v1.0.5/go.mod h1:jkl=
+golang.org/x/mod v0.14.0/go.mod h1:mno=
+`,
+		"cmd/main.go": "package main\n",
+	})
+
+	r := &goPureProjectRepository{}
+	info, err := r.LoadModuleInfo(filepath.Join(dir, "cmd"))
+	if err != nil {
+		t.Fatalf("LoadModuleInfo failed: %v", err)
+	}
+
+	if len(info.MainModules) != 1 || info.MainModules[0].Path != "example.com/app" {
+		t.Errorf("main modules: got %+v, want example.com/app", info.MainModules)
+	}
+	want := map[string]string{
+		"github.com/spf13/cobra": "v1.8.0",
+		"gopkg.in/yaml.v3":       "v3.0.1",
+		"github.com/spf13/pflag": "v1.0.5",
+	}
+	if !reflect.DeepEqual(info.Versions, want) {
+		t.Errorf("versions: got %v, want %v", info.Versions, want)
+	}
+}
+
+func TestLoadModuleInfoFromWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.work":        "go 1.22\n\nuse (\n\t./app\n\t./lib\n)\n",
+		"app/go.mod":     "module example.com/app\n\ngo 1.22\n\nrequire example.com/lib v0.0.0\n",
+		"app/main.go":    "package main\n",
+		"lib/go.mod":     "module example.com/lib\n\ngo 1.22\n\nrequire github.com/spf13/cobra v1.8.0\n",
+		"lib/lib.go":     "package lib\n",
+		"other/go.mod":   "module example.com/other\n",
+		"other/other.go": "package other\n",
+	})
+
+	r := &goPureProjectRepository{}
+	info, err := r.LoadModuleInfo(filepath.Join(dir, "app"))
+	if err != nil {
+		t.Fatalf("LoadModuleInfo failed: %v", err)
+	}
+
+	var got []string
+	for _, module := range info.MainModules {
+		got = append(got, module.Path)
+	}
+	if want := []string{"example.com/app", "example.com/lib"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("main modules: got %v, want %v", got, want)
+	}
+	if got := info.Versions["github.com/spf13/cobra"]; got != "v1.8.0" {
+		t.Errorf("cobra version: got %q, want v1.8.0", got)
+	}
+}
+
+func TestReadGoSum(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.sum": `github.com/spf13/pflag v1.0.5 h1:abc=
+github.com/spf13/pflag v1.0.5/go.mod h1:def=
+github.com/spf13/pflag v1.0.10 h1:ghi=
+github.com/spf13/pflag v1.0.10/go.mod h1:jkl=
+golang.org/x/mod v0.14.0/go.mod h1:mno=
+`,
+	})
+
+	got := readGoSum(filepath.Join(dir, "go.sum"))
+	want := map[string]string{"github.com/spf13/pflag": "v1.0.10"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
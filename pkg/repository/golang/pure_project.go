@@ -3,10 +3,14 @@ package golang
 import (
 	"fmt"
 	"go/ast"
+	"go/build"
+	"go/build/constraint"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/ryo-arima/ctree/pkg/entity/model"
@@ -14,10 +18,42 @@ import (
 
 // GoPureProjectRepository handles Go pure project file operations
 type GoPureProjectRepository interface {
-	FindGoFiles(sourcePath string, recursive bool, maxDepth int) ([]string, error)
+	FindGoFiles(sourcePath string, recursive bool, maxDepth int, opts BuildOptions) ([]string, error)
 	ParseGoFile(filePath string) (*ast.File, *token.FileSet, error)
 	ExtractFunctions(file *ast.File, fset *token.FileSet, filePath string) ([]model.Function, error)
+	ExtractSignature(funcType *ast.FuncType) ([]model.Parameter, []string)
+	ExtractTypeParams(typeParams *ast.FieldList) []model.Parameter
 	ExtractImports(file *ast.File) map[string]string // alias/name -> full import path
+	ExtractImportDecls(file *ast.File, fset *token.FileSet) []model.Import
+	ExtractTypes(file *ast.File, pkg *GoPackage, filePath string) []model.Class
+	ExtractVariables(file *ast.File, pkg *GoPackage, filePath string) []model.Variable
+	ExtractBuildConstraint(file *ast.File, filePath string) string
+	LoadPackages(sourceRoot string, filePaths []string, opts BuildOptions) ([]*GoPackage, []error)
+	LoadModuleInfo(sourcePath string) (*ModuleInfo, error)
+	FindDependencyPackage(pkgPath string, modules *ModuleInfo) (*DependencyPackage, error)
+	IsStandardPackage(pkgPath string) bool
+}
+
+// GoPackage represents a parsed and type-checked Go package
+type GoPackage struct {
+	Dir        string
+	Name       string
+	Path       string // import path derived from the enclosing go.mod, or from the source root outside a module
+	FilePaths  []string
+	Files      []*ast.File
+	Fset       *token.FileSet
+	Types      *types.Package
+	Info       *types.Info
+	TypeErrors []error
+}
+
+// BuildOptions controls which files are selected by build constraints
+type BuildOptions struct {
+	GOOS         string   // target operating system (default: host)
+	GOARCH       string   // target architecture (default: host)
+	Tags         []string // additional build tags
+	AllPlatforms bool     // select files for every platform instead of evaluating constraints; GOOS, GOARCH and Tags are not used
+	IncludeTests bool     // select _test.go files as well
 }
 
 type goPureProjectRepository struct {
@@ -28,9 +64,10 @@ func NewGoPureProjectRepository() GoPureProjectRepository {
 	return &goPureProjectRepository{}
 }
 
-// FindGoFiles finds all Go files in the specified path
-func (r *goPureProjectRepository) FindGoFiles(sourcePath string, recursive bool, maxDepth int) ([]string, error) {
+// FindGoFiles finds all Go files in the specified path that match the build options
+func (r *goPureProjectRepository) FindGoFiles(sourcePath string, recursive bool, maxDepth int, opts BuildOptions) ([]string, error) {
 	var goFiles []string
+	match := r.newFileMatcher(opts)
 
 	// Get absolute path
 	absPath, err := filepath.Abs(sourcePath)
@@ -46,14 +83,14 @@ func (r *goPureProjectRepository) FindGoFiles(sourcePath string, recursive bool,
 
 	// If it's a single file
 	if !info.IsDir() {
-		if strings.HasSuffix(absPath, ".go") && !strings.HasSuffix(absPath, "_test.go") {
+		if match(filepath.Dir(absPath), filepath.Base(absPath)) {
 			return []string{absPath}, nil
 		}
 		return []string{}, nil
 	}
 
 	// Walk directory
-	err = r.walkDir(absPath, absPath, 0, maxDepth, recursive, &goFiles)
+	err = r.walkDir(absPath, absPath, 0, maxDepth, recursive, match, &goFiles)
 	if err != nil {
 		return nil, err
 	}
@@ -62,7 +99,7 @@ func (r *goPureProjectRepository) FindGoFiles(sourcePath string, recursive bool,
 }
 
 // walkDir recursively walks through directories
-func (r *goPureProjectRepository) walkDir(basePath, currentPath string, currentDepth, maxDepth int, recursive bool, goFiles *[]string) error {
+func (r *goPureProjectRepository) walkDir(basePath, currentPath string, currentDepth, maxDepth int, recursive bool, match func(dir, name string) bool, goFiles *[]string) error {
 	if currentDepth > maxDepth {
 		return nil
 	}
@@ -87,11 +124,11 @@ func (r *goPureProjectRepository) walkDir(basePath, currentPath string, currentD
 
 		if entry.IsDir() {
 			if recursive {
-				if err := r.walkDir(basePath, fullPath, currentDepth+1, maxDepth, recursive, goFiles); err != nil {
+				if err := r.walkDir(basePath, fullPath, currentDepth+1, maxDepth, recursive, match, goFiles); err != nil {
 					return err
 				}
 			}
-		} else if strings.HasSuffix(entry.Name(), ".go") && !strings.HasSuffix(entry.Name(), "_test.go") {
+		} else if match(currentPath, entry.Name()) {
 			*goFiles = append(*goFiles, fullPath)
 		}
 	}
@@ -99,6 +136,38 @@ func (r *goPureProjectRepository) walkDir(basePath, currentPath string, currentD
 	return nil
 }
 
+// newFileMatcher returns a function reporting whether a file in a directory is selected
+// by the build options, evaluating build constraints the way the go tool does
+func (r *goPureProjectRepository) newFileMatcher(opts BuildOptions) func(dir, name string) bool {
+	ctx := r.newBuildContext(opts)
+	return func(dir, name string) bool {
+		if !strings.HasSuffix(name, ".go") {
+			return false
+		}
+		if strings.HasSuffix(name, "_test.go") && !opts.IncludeTests {
+			return false
+		}
+		if opts.AllPlatforms {
+			return true
+		}
+		matched, err := ctx.MatchFile(dir, name)
+		return err == nil && matched
+	}
+}
+
+// newBuildContext returns the host build context with the GOOS, GOARCH and tags of the build options
+func (r *goPureProjectRepository) newBuildContext(opts BuildOptions) build.Context {
+	ctx := build.Default
+	if opts.GOOS != "" {
+		ctx.GOOS = opts.GOOS
+	}
+	if opts.GOARCH != "" {
+		ctx.GOARCH = opts.GOARCH
+	}
+	ctx.BuildTags = opts.Tags
+	return ctx
+}
+
 // ParseGoFile parses a Go source file
 func (r *goPureProjectRepository) ParseGoFile(filePath string) (*ast.File, *token.FileSet, error) {
 	fset := token.NewFileSet()
@@ -120,47 +189,23 @@ func (r *goPureProjectRepository) ExtractFunctions(file *ast.File, fset *token.F
 				Name:    x.Name.Name,
 				File:    filePath,
 				Line:    fset.Position(x.Pos()).Line,
+				EndLine: fset.Position(x.End()).Line,
 				Package: file.Name.Name,
 				Kind:    "function",
 			}
 
-			// Extract receiver type for methods
-			if x.Recv != nil && len(x.Recv.List) > 0 {
-				if starExpr, ok := x.Recv.List[0].Type.(*ast.StarExpr); ok {
-					if ident, ok := starExpr.X.(*ast.Ident); ok {
-						fn.Receiver = ident.Name
-					}
-				} else if ident, ok := x.Recv.List[0].Type.(*ast.Ident); ok {
-					fn.Receiver = ident.Name
-				}
+			if x.Doc != nil {
+				fn.Doc = strings.TrimSpace(x.Doc.Text())
 			}
 
-			// Extract parameters
-			if x.Type.Params != nil {
-				for _, param := range x.Type.Params.List {
-					paramType := formatType(param.Type)
-					if len(param.Names) > 0 {
-						for _, name := range param.Names {
-							fn.Parameters = append(fn.Parameters, model.Parameter{
-								Name: name.Name,
-								Type: paramType,
-							})
-						}
-					} else {
-						fn.Parameters = append(fn.Parameters, model.Parameter{
-							Type: paramType,
-						})
-					}
-				}
+			// Extract receiver type for methods
+			if x.Recv != nil && len(x.Recv.List) > 0 {
+				fn.Receiver = receiverTypeName(x.Recv.List[0].Type)
 			}
 
-			// Extract return types
-			if x.Type.Results != nil {
-				for _, result := range x.Type.Results.List {
-					returnType := formatType(result.Type)
-					fn.ReturnTypes = append(fn.ReturnTypes, returnType)
-				}
-			}
+			// Extract type parameters, parameters and return types
+			fn.TypeParams = r.ExtractTypeParams(x.Type.TypeParams)
+			fn.Parameters, fn.ReturnTypes = r.ExtractSignature(x.Type)
 
 			functions = append(functions, fn)
 		}
@@ -170,6 +215,59 @@ func (r *goPureProjectRepository) ExtractFunctions(file *ast.File, fset *token.F
 	return functions, nil
 }
 
+// receiverTypeName returns the base type name of a method receiver,
+// dropping pointers and the type parameters of generic receivers
+func receiverTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return receiverTypeName(t.X)
+	case *ast.ParenExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(t.X)
+	}
+	return ""
+}
+
+// ExtractSignature extracts parameters and return types from a function type
+func (r *goPureProjectRepository) ExtractSignature(funcType *ast.FuncType) ([]model.Parameter, []string) {
+	var parameters []model.Parameter
+	var returnTypes []string
+
+	// Extract parameters
+	if funcType.Params != nil {
+		for _, param := range funcType.Params.List {
+			paramType := formatType(param.Type)
+			if len(param.Names) > 0 {
+				for _, name := range param.Names {
+					parameters = append(parameters, model.Parameter{
+						Name: name.Name,
+						Type: paramType,
+					})
+				}
+			} else {
+				parameters = append(parameters, model.Parameter{
+					Type: paramType,
+				})
+			}
+		}
+	}
+
+	// Extract return types
+	if funcType.Results != nil {
+		for _, result := range funcType.Results.List {
+			returnType := formatType(result.Type)
+			returnTypes = append(returnTypes, returnType)
+		}
+	}
+
+	return parameters, returnTypes
+}
+
 // formatType formats an AST type expression to string
 func formatType(expr ast.Expr) string {
 	switch t := expr.(type) {
@@ -178,22 +276,97 @@ func formatType(expr ast.Expr) string {
 	case *ast.StarExpr:
 		return "*" + formatType(t.X)
 	case *ast.ArrayType:
+		if t.Len != nil {
+			return "[" + types.ExprString(t.Len) + "]" + formatType(t.Elt)
+		}
 		return "[]" + formatType(t.Elt)
 	case *ast.MapType:
 		return "map[" + formatType(t.Key) + "]" + formatType(t.Value)
 	case *ast.SelectorExpr:
 		return formatType(t.X) + "." + t.Sel.Name
-	case *ast.InterfaceType:
-		return "interface{}"
 	case *ast.ChanType:
+		switch t.Dir {
+		case ast.SEND:
+			return "chan<- " + formatType(t.Value)
+		case ast.RECV:
+			return "<-chan " + formatType(t.Value)
+		}
 		return "chan " + formatType(t.Value)
 	case *ast.FuncType:
-		return "func"
+		return "func" + formatFuncType(t)
 	case *ast.Ellipsis:
 		return "..." + formatType(t.Elt)
+	case *ast.IndexExpr:
+		// Generic instantiation with a single type argument: List[T]
+		return formatType(t.X) + "[" + formatType(t.Index) + "]"
+	case *ast.IndexListExpr:
+		// Generic instantiation with several type arguments: Map[K, V]
+		var args []string
+		for _, index := range t.Indices {
+			args = append(args, formatType(index))
+		}
+		return formatType(t.X) + "[" + strings.Join(args, ", ") + "]"
 	default:
-		return "unknown"
+		// Struct, interface and constraint expressions
+		return types.ExprString(expr)
+	}
+}
+
+// formatFuncType formats the parameter and result lists of a function type
+func formatFuncType(t *ast.FuncType) string {
+	var params []string
+	if t.Params != nil {
+		for _, param := range t.Params.List {
+			paramType := formatType(param.Type)
+			if len(param.Names) > 0 {
+				for _, name := range param.Names {
+					params = append(params, name.Name+" "+paramType)
+				}
+			} else {
+				params = append(params, paramType)
+			}
+		}
+	}
+	signature := "(" + strings.Join(params, ", ") + ")"
+
+	if t.Results != nil {
+		var results []string
+		for _, result := range t.Results.List {
+			resultType := formatType(result.Type)
+			if len(result.Names) > 0 {
+				for _, name := range result.Names {
+					results = append(results, name.Name+" "+resultType)
+				}
+			} else {
+				results = append(results, resultType)
+			}
+		}
+		if len(results) == 1 && len(t.Results.List[0].Names) == 0 {
+			signature += " " + results[0]
+		} else {
+			signature += " (" + strings.Join(results, ", ") + ")"
+		}
+	}
+
+	return signature
+}
+
+// ExtractTypeParams extracts type parameters and their constraints from a type parameter list
+func (r *goPureProjectRepository) ExtractTypeParams(typeParams *ast.FieldList) []model.Parameter {
+	var params []model.Parameter
+	if typeParams == nil {
+		return params
 	}
+	for _, field := range typeParams.List {
+		constraint := formatType(field.Type)
+		for _, name := range field.Names {
+			params = append(params, model.Parameter{
+				Name: name.Name,
+				Type: constraint,
+			})
+		}
+	}
+	return params
 }
 
 // ExtractImports extracts import information from AST
@@ -225,3 +398,359 @@ func (r *goPureProjectRepository) ExtractImports(file *ast.File) map[string]stri
 
 	return imports
 }
+
+// ExtractImportDecls extracts every import declaration of a file with its explicit name
+func (r *goPureProjectRepository) ExtractImportDecls(file *ast.File, fset *token.FileSet) []model.Import {
+	var imports []model.Import
+
+	for _, imp := range file.Imports {
+		if imp.Path == nil {
+			continue
+		}
+		importPath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		decl := model.Import{
+			Path: importPath,
+			Line: fset.Position(imp.Pos()).Line,
+		}
+		if imp.Name != nil {
+			decl.Name = imp.Name.Name
+			decl.Blank = decl.Name == "_"
+			decl.Dot = decl.Name == "."
+		}
+		imports = append(imports, decl)
+	}
+
+	return imports
+}
+
+// LoadPackages parses the given files with a shared file set, groups them by
+// directory and package name, and type-checks each group.
+// Files that fail to parse are skipped and reported in the returned errors.
+// Type errors do not abort loading; they are recorded on the package so that
+// callers can fall back to name-based resolution for unresolved expressions.
+// Imported packages outside the given files are selected with the build options as well.
+// Packages outside any module get import paths relative to sourceRoot.
+func (r *goPureProjectRepository) LoadPackages(sourceRoot string, filePaths []string, opts BuildOptions) ([]*GoPackage, []error) {
+	fset := token.NewFileSet()
+	var parseErrors []error
+	var packages []*GoPackage
+	packageIndex := make(map[string]*GoPackage) // dir + package name -> package
+
+	for _, filePath := range filePaths {
+		file, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
+		if err != nil {
+			parseErrors = append(parseErrors, fmt.Errorf("failed to parse file %s: %w", filePath, err))
+			continue
+		}
+
+		dir := filepath.Dir(filePath)
+		key := dir + ":" + file.Name.Name
+		pkg, ok := packageIndex[key]
+		if !ok {
+			pkg = &GoPackage{
+				Dir:  dir,
+				Name: file.Name.Name,
+				Fset: fset,
+			}
+			packageIndex[key] = pkg
+			packages = append(packages, pkg)
+		}
+		pkg.FilePaths = append(pkg.FilePaths, filePath)
+		pkg.Files = append(pkg.Files, file)
+	}
+
+	// Packages under analysis are checked once and shared between importers so that
+	// types and methods are identical across packages; everything else is type-checked from source
+	imp := &localImporter{
+		packages: make(map[string]*GoPackage),
+		fallback: newSourceImporter(r.newBuildContext(opts), fset),
+		checking: make(map[string]bool),
+		check:    r.typeCheckPackage,
+	}
+	for _, pkg := range packages {
+		pkg.Path = r.getImportPath(pkg.Dir, sourceRoot)
+		if strings.HasSuffix(pkg.Name, "_test") {
+			// External test packages share the directory with the package they test
+			pkg.Path += "_test"
+		}
+		imp.packages[pkg.Path] = pkg
+	}
+	for _, pkg := range packages {
+		if pkg.Types == nil {
+			imp.load(pkg)
+		}
+	}
+
+	return packages, parseErrors
+}
+
+// typeCheckPackage runs go/types over a package and stores the collected type information
+func (r *goPureProjectRepository) typeCheckPackage(pkg *GoPackage, imp types.Importer) {
+	pkg.Info = &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		Instances:  make(map[*ast.Ident]types.Instance),
+	}
+
+	conf := types.Config{
+		Importer: imp,
+		Error: func(err error) {
+			pkg.TypeErrors = append(pkg.TypeErrors, err)
+		},
+	}
+
+	// The returned error is the first one reported through conf.Error, which is already recorded
+	pkg.Types, _ = conf.Check(pkg.Path, pkg.Fset, pkg.Files, pkg.Info)
+}
+
+// getImportPath derives the import path of a package directory from the nearest go.mod.
+// Vendored packages keep the import path they are vendored under.
+func (r *goPureProjectRepository) getImportPath(dir string, sourceRoot string) string {
+	if slashDir := filepath.ToSlash(dir); strings.Contains(slashDir, "/vendor/") {
+		return slashDir[strings.LastIndex(slashDir, "/vendor/")+len("/vendor/"):]
+	}
+	for modRoot := dir; ; modRoot = filepath.Dir(modRoot) {
+		data, err := os.ReadFile(filepath.Join(modRoot, "go.mod"))
+		if err == nil {
+			modulePath := parseModulePath(data)
+			if modulePath == "" {
+				return r.getSourceRootPath(dir, sourceRoot)
+			}
+			rel, err := filepath.Rel(modRoot, dir)
+			if err != nil || rel == "." {
+				return modulePath
+			}
+			return modulePath + "/" + filepath.ToSlash(rel)
+		}
+		if filepath.Dir(modRoot) == modRoot {
+			return r.getSourceRootPath(dir, sourceRoot)
+		}
+	}
+}
+
+// getSourceRootPath derives the import path of a package directory outside any module.
+// The path starts with the name of the source root directory, so that the root package
+// gets a path of its own, followed by the directory relative to the source root.
+func (r *goPureProjectRepository) getSourceRootPath(dir string, sourceRoot string) string {
+	if sourceRoot == "" {
+		return dir
+	}
+	absRoot, err := filepath.Abs(sourceRoot)
+	if err != nil {
+		return dir
+	}
+	if info, err := os.Stat(absRoot); err == nil && !info.IsDir() {
+		absRoot = filepath.Dir(absRoot)
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return dir
+	}
+	rel, err := filepath.Rel(absRoot, absDir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return dir
+	}
+	if rel == "." {
+		return filepath.Base(absRoot)
+	}
+	return filepath.Base(absRoot) + "/" + filepath.ToSlash(rel)
+}
+
+// IsStandardPackage reports whether an import path names a package of the Go standard library.
+// Without a GOROOT to look into, every path without a dot in its first element counts as one.
+func (r *goPureProjectRepository) IsStandardPackage(pkgPath string) bool {
+	firstElem, _, _ := strings.Cut(pkgPath, "/")
+	if strings.Contains(firstElem, ".") {
+		return false
+	}
+	// The cgo pseudo-package has no source directory
+	if pkgPath == "C" {
+		return true
+	}
+	srcDir := filepath.Join(build.Default.GOROOT, "src")
+	if info, err := os.Stat(srcDir); build.Default.GOROOT == "" || err != nil || !info.IsDir() {
+		return true
+	}
+	info, err := os.Stat(filepath.Join(srcDir, filepath.FromSlash(pkgPath)))
+	return err == nil && info.IsDir()
+}
+
+// localImporter resolves imports of packages under analysis to their already checked
+// *types.Package and delegates all other imports to the fallback importer
+type localImporter struct {
+	packages map[string]*GoPackage // import path -> package under analysis
+	fallback types.ImporterFrom
+	checking map[string]bool
+	check    func(pkg *GoPackage, imp types.Importer)
+}
+
+// Import implements types.Importer
+func (i *localImporter) Import(path string) (*types.Package, error) {
+	return i.ImportFrom(path, "", 0)
+}
+
+// ImportFrom implements types.ImporterFrom
+func (i *localImporter) ImportFrom(path, dir string, mode types.ImportMode) (*types.Package, error) {
+	pkg, ok := i.packages[path]
+	if !ok {
+		return i.fallback.ImportFrom(path, dir, mode)
+	}
+	if i.checking[path] {
+		return nil, fmt.Errorf("import cycle through %s", path)
+	}
+	if pkg.Types == nil {
+		i.load(pkg)
+	}
+	return pkg.Types, nil
+}
+
+// load type-checks a package under analysis, importing its dependencies on demand
+func (i *localImporter) load(pkg *GoPackage) {
+	i.checking[pkg.Path] = true
+	defer delete(i.checking, pkg.Path)
+	i.check(pkg, i)
+}
+
+// sourceImporter type-checks imported packages from source like the "source" compiler of
+// go/importer, but selects their files with its own build context instead of build.Default
+type sourceImporter struct {
+	ctx      build.Context
+	fset     *token.FileSet
+	sizes    types.Sizes
+	packages map[string]*types.Package // import path -> package, nil while it is being checked
+}
+
+// newSourceImporter returns a source importer for a build context. Cgo is disabled so that
+// packages such as net and os/user are checked from their pure Go files.
+func newSourceImporter(ctx build.Context, fset *token.FileSet) *sourceImporter {
+	ctx.CgoEnabled = false
+	return &sourceImporter{
+		ctx:      ctx,
+		fset:     fset,
+		sizes:    types.SizesFor(ctx.Compiler, ctx.GOARCH),
+		packages: make(map[string]*types.Package),
+	}
+}
+
+// Import implements types.Importer
+func (i *sourceImporter) Import(path string) (*types.Package, error) {
+	return i.ImportFrom(path, ".", 0)
+}
+
+// ImportFrom implements types.ImporterFrom
+func (i *sourceImporter) ImportFrom(path, dir string, mode types.ImportMode) (*types.Package, error) {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	bp, err := i.ctx.Import(path, dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	if bp.ImportPath == "unsafe" {
+		return types.Unsafe, nil
+	}
+	if pkg, ok := i.packages[bp.ImportPath]; ok {
+		if pkg == nil {
+			return nil, fmt.Errorf("import cycle through %s", bp.ImportPath)
+		}
+		return pkg, nil
+	}
+
+	i.packages[bp.ImportPath] = nil
+	var files []*ast.File
+	for _, name := range bp.GoFiles {
+		file, err := parser.ParseFile(i.fset, filepath.Join(bp.Dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			delete(i.packages, bp.ImportPath)
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	// Only the exported API is needed, so function bodies are skipped
+	conf := types.Config{
+		IgnoreFuncBodies: true,
+		Importer:         i,
+		Sizes:            i.sizes,
+	}
+	pkg, err := conf.Check(bp.ImportPath, i.fset, files, nil)
+	if err != nil {
+		delete(i.packages, bp.ImportPath)
+		return nil, fmt.Errorf("type-checking package %q failed: %w", bp.ImportPath, err)
+	}
+	i.packages[bp.ImportPath] = pkg
+	return pkg, nil
+}
+
+// knownOS and knownArch list the GOOS and GOARCH values recognized in file name suffixes
+var (
+	knownOS = map[string]bool{
+		"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true,
+		"hurd": true, "illumos": true, "ios": true, "js": true, "linux": true, "nacl": true,
+		"netbsd": true, "openbsd": true, "plan9": true, "solaris": true, "wasip1": true,
+		"windows": true, "zos": true,
+	}
+	knownArch = map[string]bool{
+		"386": true, "amd64": true, "amd64p32": true, "arm": true, "armbe": true, "arm64": true,
+		"arm64be": true, "loong64": true, "mips": true, "mipsle": true, "mips64": true,
+		"mips64le": true, "mips64p32": true, "mips64p32le": true, "ppc": true, "ppc64": true,
+		"ppc64le": true, "riscv": true, "riscv64": true, "s390": true, "s390x": true,
+		"sparc": true, "sparc64": true, "wasm": true,
+	}
+)
+
+// ExtractBuildConstraint returns the build constraint of a file, combining its //go:build
+// (or legacy // +build) lines with GOOS/GOARCH file name suffixes.
+// An empty string means the file builds on every platform.
+func (r *goPureProjectRepository) ExtractBuildConstraint(file *ast.File, filePath string) string {
+	var exprs []string
+
+	// Build constraints must appear before the package clause
+groups:
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			break
+		}
+		for _, comment := range group.List {
+			if !constraint.IsGoBuild(comment.Text) && !constraint.IsPlusBuild(comment.Text) {
+				continue
+			}
+			expr, err := constraint.Parse(comment.Text)
+			if err != nil {
+				continue
+			}
+			if constraint.IsGoBuild(comment.Text) {
+				// //go:build supersedes any // +build lines, including those in later groups
+				exprs = []string{expr.String()}
+				break groups
+			}
+			exprs = append(exprs, expr.String())
+		}
+	}
+
+	// File name suffixes: name_GOOS.go, name_GOARCH.go, name_GOOS_GOARCH.go
+	name := strings.TrimSuffix(filepath.Base(filePath), ".go")
+	name = strings.TrimSuffix(name, "_test")
+	parts := strings.Split(name, "_")
+	n := len(parts)
+	if n >= 3 && knownOS[parts[n-2]] && knownArch[parts[n-1]] {
+		exprs = append(exprs, parts[n-2], parts[n-1])
+	} else if n >= 2 && (knownOS[parts[n-1]] || knownArch[parts[n-1]]) {
+		exprs = append(exprs, parts[n-1])
+	}
+
+	if len(exprs) > 1 {
+		for i, expr := range exprs {
+			if strings.Contains(expr, "||") {
+				exprs[i] = "(" + expr + ")"
+			}
+		}
+	}
+	return strings.Join(exprs, " && ")
+}
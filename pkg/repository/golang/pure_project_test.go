@@ -0,0 +1,209 @@
+package golang
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindGoFilesMatchesBuildOptions(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":         "package main\n",
+		"debug.go":        "//go:build debug\n\npackage main\n",
+		"file_linux.go":   "package main\n",
+		"file_windows.go": "package main\n",
+		"main_test.go":    "package main\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		opts BuildOptions
+		want []string
+	}{
+		{"linux", BuildOptions{GOOS: "linux", GOARCH: "amd64"}, []string{"file_linux.go", "main.go"}},
+		{"windows", BuildOptions{GOOS: "windows", GOARCH: "amd64"}, []string{"file_windows.go", "main.go"}},
+		{"tags", BuildOptions{GOOS: "linux", GOARCH: "amd64", Tags: []string{"debug"}}, []string{"debug.go", "file_linux.go", "main.go"}},
+		{"all platforms", BuildOptions{AllPlatforms: true}, []string{"debug.go", "file_linux.go", "file_windows.go", "main.go"}},
+	}
+
+	r := &goPureProjectRepository{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths, err := r.FindGoFiles(dir, true, 10, tt.opts)
+			if err != nil {
+				t.Fatalf("FindGoFiles failed: %v", err)
+			}
+			var got []string
+			for _, path := range paths {
+				got = append(got, filepath.Base(path))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("files: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractBuildConstraint(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		src      string
+		want     string
+	}{
+		{
+			name:     "no constraint",
+			fileName: "main.go",
+			src:      "package main\n",
+			want:     "",
+		},
+		{
+			name:     "go:build line",
+			fileName: "main.go",
+			src:      "//go:build linux || darwin\n\npackage main\n",
+			want:     "linux || darwin",
+		},
+		{
+			name:     "legacy +build lines are combined",
+			fileName: "main.go",
+			src:      "// +build linux\n// +build amd64\n\npackage main\n",
+			want:     "linux && amd64",
+		},
+		{
+			name:     "go:build supersedes +build in the same group",
+			fileName: "main.go",
+			src:      "//go:build linux\n// +build linux darwin\n\npackage main\n",
+			want:     "linux",
+		},
+		{
+			name:     "go:build supersedes +build in later groups",
+			fileName: "main.go",
+			src:      "//go:build linux\n\n// +build darwin\n\npackage main\n",
+			want:     "linux",
+		},
+		{
+			name:     "comments after the package clause are ignored",
+			fileName: "main.go",
+			src:      "package main\n\n//go:build linux\n",
+			want:     "",
+		},
+		{
+			name:     "GOOS file name suffix",
+			fileName: "file_windows.go",
+			src:      "package main\n",
+			want:     "windows",
+		},
+		{
+			name:     "GOOS and GOARCH file name suffix",
+			fileName: "file_linux_arm64_test.go",
+			src:      "package main\n",
+			want:     "linux && arm64",
+		},
+		{
+			name:     "go:build line combined with a file name suffix",
+			fileName: "file_amd64.go",
+			src:      "//go:build linux || darwin\n\npackage main\n",
+			want:     "(linux || darwin) && amd64",
+		},
+	}
+
+	r := &goPureProjectRepository{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := parser.ParseFile(token.NewFileSet(), tt.fileName, tt.src, parser.ParseComments)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			if got := r.ExtractBuildConstraint(file, tt.fileName); got != tt.want {
+				t.Errorf("ExtractBuildConstraint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadPackagesImportsForBuildOptions(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.22\n",
+		// GetCurrentProcess is only declared in the windows files of syscall
+		"main.go": "package main\n\nimport \"syscall\"\n\nfunc main() { syscall.GetCurrentProcess() }\n",
+	})
+
+	tests := []struct {
+		name       string
+		opts       BuildOptions
+		wantErrors bool
+	}{
+		{"windows", BuildOptions{GOOS: "windows", GOARCH: "amd64"}, false},
+		{"linux", BuildOptions{GOOS: "linux", GOARCH: "amd64"}, true},
+	}
+
+	r := &goPureProjectRepository{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packages, parseErrors := r.LoadPackages(dir, []string{filepath.Join(dir, "main.go")}, tt.opts)
+			if len(parseErrors) > 0 || len(packages) != 1 {
+				t.Fatalf("got %d packages and parse errors %v, want 1 package", len(packages), parseErrors)
+			}
+			if got := len(packages[0].TypeErrors) > 0; got != tt.wantErrors {
+				t.Errorf("type errors %v, want errors: %v", packages[0].TypeErrors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestLoadPackagesOutsideModule(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "proj")
+	writeFiles(t, root, map[string]string{
+		"main.go":      "package main\n\nimport \"proj/util\"\n\nfunc main() { util.Do() }\n",
+		"util/util.go": "package util\n\nfunc Do() {}\n",
+	})
+
+	r := &goPureProjectRepository{}
+	files := []string{filepath.Join(root, "main.go"), filepath.Join(root, "util", "util.go")}
+	packages, parseErrors := r.LoadPackages(root, files, BuildOptions{})
+	if len(parseErrors) > 0 {
+		t.Fatalf("parse errors: %v", parseErrors)
+	}
+
+	got := make(map[string]string)
+	for _, pkg := range packages {
+		got[pkg.Name] = pkg.Path
+		if len(pkg.TypeErrors) > 0 {
+			t.Errorf("type errors in %s: %v", pkg.Path, pkg.TypeErrors)
+		}
+	}
+	want := map[string]string{"main": "proj", "util": "proj/util"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestIsStandardPackage(t *testing.T) {
+	tests := []struct {
+		pkgPath string
+		want    bool
+	}{
+		{"fmt", true},
+		{"net/http", true},
+		{"C", true},
+		{"proj/util", false},
+		{"example.com/app", false},
+	}
+
+	r := &goPureProjectRepository{}
+	for _, tt := range tests {
+		if got := r.IsStandardPackage(tt.pkgPath); got != tt.want {
+			t.Errorf("IsStandardPackage(%q) = %v, want %v", tt.pkgPath, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package golang
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"github.com/ryo-arima/ctree/pkg/entity/model"
+)
+
+// ExtractTypes extracts the package-level type declarations of a file.
+// Method sets come from the type checker and are left empty when type checking failed.
+func (r *goPureProjectRepository) ExtractTypes(file *ast.File, pkg *GoPackage, filePath string) []model.Class {
+	var classes []model.Class
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+
+			class := model.Class{
+				Name:        typeSpec.Name.Name,
+				Package:     file.Name.Name,
+				PackagePath: pkg.Path,
+				File:        filePath,
+				Line:        pkg.Fset.Position(typeSpec.Pos()).Line,
+				TypeParams:  r.ExtractTypeParams(typeSpec.TypeParams),
+			}
+
+			// A doc comment on an ungrouped declaration belongs to its only spec
+			doc := typeSpec.Doc
+			if doc == nil && !gen.Lparen.IsValid() {
+				doc = gen.Doc
+			}
+			if doc != nil {
+				class.Doc = strings.TrimSpace(doc.Text())
+			}
+
+			switch t := typeSpec.Type.(type) {
+			case *ast.StructType:
+				class.Kind = "struct"
+				class.Fields, class.Embeds = r.extractFields(t)
+			case *ast.InterfaceType:
+				class.Kind = "interface"
+				for _, method := range t.Methods.List {
+					if len(method.Names) == 0 {
+						class.Embeds = append(class.Embeds, formatType(method.Type))
+					}
+				}
+			default:
+				class.Kind = "named"
+				class.Underlying = formatType(typeSpec.Type)
+			}
+			if typeSpec.Assign.IsValid() {
+				class.Kind = "alias"
+				class.Underlying = formatType(typeSpec.Type)
+			}
+
+			class.Methods = r.extractMethodSet(typeSpec, pkg)
+			classes = append(classes, class)
+		}
+	}
+
+	return classes
+}
+
+// extractFields returns the fields of a struct type and the types it embeds
+func (r *goPureProjectRepository) extractFields(st *ast.StructType) ([]model.Field, []string) {
+	var fields []model.Field
+	var embeds []string
+
+	for _, field := range st.Fields.List {
+		fieldType := formatType(field.Type)
+		var tag string
+		if field.Tag != nil {
+			if unquoted, err := strconv.Unquote(field.Tag.Value); err == nil {
+				tag = unquoted
+			}
+		}
+
+		if len(field.Names) == 0 {
+			// Embedded fields are named after their type without pointer and package
+			name := strings.TrimPrefix(fieldType, "*")
+			if i := strings.Index(name, "["); i >= 0 {
+				name = name[:i]
+			}
+			if i := strings.LastIndex(name, "."); i >= 0 {
+				name = name[i+1:]
+			}
+			fields = append(fields, model.Field{Name: name, Type: fieldType, Tag: tag, Embedded: true})
+			embeds = append(embeds, fieldType)
+			continue
+		}
+		for _, name := range field.Names {
+			fields = append(fields, model.Field{Name: name.Name, Type: fieldType, Tag: tag})
+		}
+	}
+
+	return fields, embeds
+}
+
+// extractMethodSet returns the methods of a declared type, including those promoted
+// through embedding. For non-interface types the method set of *T is used, so that
+// methods with pointer receivers are listed too.
+func (r *goPureProjectRepository) extractMethodSet(typeSpec *ast.TypeSpec, pkg *GoPackage) []model.ClassMethod {
+	if pkg.Info == nil {
+		return nil
+	}
+	typeName, ok := pkg.Info.Defs[typeSpec.Name].(*types.TypeName)
+	if !ok || typeName.IsAlias() {
+		return nil
+	}
+
+	t := typeName.Type()
+	if !types.IsInterface(t) {
+		t = types.NewPointer(t)
+	}
+	qualifier := types.RelativeTo(pkg.Types)
+
+	var methods []model.ClassMethod
+	methodSet := types.NewMethodSet(t)
+	for i := 0; i < methodSet.Len(); i++ {
+		selection := methodSet.At(i)
+		fn, ok := selection.Obj().(*types.Func)
+		if !ok {
+			continue
+		}
+		method := model.ClassMethod{
+			Name:      fn.Name(),
+			Signature: strings.TrimPrefix(types.TypeString(fn.Type(), qualifier), "func"),
+			Via:       EmbeddingPath(selection),
+		}
+		if recv := fn.Signature().Recv(); recv != nil {
+			_, method.Pointer = recv.Type().(*types.Pointer)
+		}
+		methods = append(methods, method)
+	}
+
+	return methods
+}
+
+// EmbeddingPath returns the embedded fields a selection is promoted through, joined with dots,
+// or "" when the method or field is declared directly on the receiver type
+func EmbeddingPath(selection *types.Selection) string {
+	if selection == nil || len(selection.Index()) < 2 {
+		return ""
+	}
+
+	var names []string
+	t := selection.Recv()
+	index := selection.Index()
+	for _, i := range index[:len(index)-1] {
+		if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		st, ok := types.Unalias(t).Underlying().(*types.Struct)
+		if !ok {
+			break
+		}
+		field := st.Field(i)
+		names = append(names, field.Name())
+		t = field.Type()
+	}
+	return strings.Join(names, ".")
+}
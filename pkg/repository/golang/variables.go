@@ -0,0 +1,108 @@
+package golang
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/ryo-arima/ctree/pkg/entity/model"
+)
+
+// ExtractVariables extracts the package-level var and const declarations of a file.
+// Constants without an expression repeat the previous expression of their block, as
+// in iota enumerations.
+func (r *goPureProjectRepository) ExtractVariables(file *ast.File, pkg *GoPackage, filePath string) []model.Variable {
+	var variables []model.Variable
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || (gen.Tok != token.VAR && gen.Tok != token.CONST) {
+			continue
+		}
+
+		var lastType ast.Expr
+		var lastValues []ast.Expr
+		for _, spec := range gen.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+
+			typeExpr, values := valueSpec.Type, valueSpec.Values
+			if gen.Tok == token.CONST {
+				if typeExpr == nil && len(values) == 0 {
+					typeExpr, values = lastType, lastValues
+				} else {
+					lastType, lastValues = typeExpr, values
+				}
+			}
+
+			doc := valueSpec.Doc
+			if doc == nil && !gen.Lparen.IsValid() {
+				doc = gen.Doc
+			}
+
+			for i, name := range valueSpec.Names {
+				if name.Name == "_" {
+					continue
+				}
+
+				variable := model.Variable{
+					Name:        name.Name,
+					Kind:        gen.Tok.String(),
+					Package:     file.Name.Name,
+					PackagePath: pkg.Path,
+					File:        filePath,
+					Line:        pkg.Fset.Position(name.Pos()).Line,
+					Exported:    name.IsExported(),
+				}
+				if doc != nil {
+					variable.Doc = strings.TrimSpace(doc.Text())
+				}
+				if typeExpr != nil {
+					variable.Type = formatType(typeExpr)
+				}
+
+				// A single call may initialize several variables
+				var value ast.Expr
+				if i < len(values) {
+					value = values[i]
+				} else if len(values) == 1 {
+					value = values[0]
+				}
+				if value != nil {
+					variable.Value = types.ExprString(value)
+					variable.Iota = gen.Tok == token.CONST && usesIota(value)
+				}
+
+				if pkg.Info != nil {
+					if obj := pkg.Info.Defs[name]; obj != nil {
+						if variable.Type == "" {
+							variable.Type = types.TypeString(obj.Type(), types.RelativeTo(pkg.Types))
+						}
+						if c, ok := obj.(*types.Const); ok && variable.Iota {
+							variable.Constant = c.Val().ExactString()
+						}
+					}
+				}
+
+				variables = append(variables, variable)
+			}
+		}
+	}
+
+	return variables
+}
+
+// usesIota reports whether a constant expression refers to iota
+func usesIota(expr ast.Expr) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && ident.Name == "iota" {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
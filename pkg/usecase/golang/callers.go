@@ -0,0 +1,184 @@
+package golang
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ryo-arima/ctree/pkg/entity/model"
+	"github.com/ryo-arima/ctree/pkg/repository/golang"
+)
+
+// callerRef represents a function calling another one
+type callerRef struct {
+	key   string
+	edge  model.CallEdge
+	sites []model.CallSite
+	field bool // the call goes through a function-typed struct field
+}
+
+// callerTreeSource provides what buildCallerNodeRecursive needs to walk the call graph upward
+type callerTreeSource struct {
+	functions   map[string]model.Function // function key -> function
+	callers     map[string][]callerRef    // callee key -> callers
+	entryPoints map[string]bool           // keys of entry points
+	imports     map[string]string         // import path -> package name, for external titles
+	modules     *golang.ModuleInfo        // nil when no go.mod was found
+}
+
+// BuildCallerTree builds a reverse call tree for each target function: the children of
+// a node are the functions calling it, up to the entry points. Targets outside the
+// analyzed source, such as os.Exit, are found through the calls recorded on every function.
+func (u *goPureProjectGenerateUsecase) BuildCallerTree(ctree *model.CTree, targets []string) []model.CallTreeNode {
+	source := &callerTreeSource{
+		functions:   make(map[string]model.Function),
+		callers:     make(map[string][]callerRef),
+		entryPoints: make(map[string]bool),
+		imports:     make(map[string]string),
+	}
+	for _, fn := range ctree.Functions {
+		source.functions[u.getFunctionKey(fn)] = fn
+	}
+	for _, ep := range ctree.EntryPoints {
+		source.entryPoints[u.getFunctionKey(ep)] = true
+	}
+	for _, imports := range ctree.FileImports {
+		for name, importPath := range imports {
+			source.imports[importPath] = name
+		}
+	}
+	// Module information tells dependencies apart from the standard library
+	if modules, err := u.repo.LoadModuleInfo(ctree.SourceFile); err == nil {
+		source.modules = modules
+	}
+
+	hasEdge := make(map[string]bool) // caller key + " " + callee key
+	for _, edge := range ctree.CallGraph {
+		hasEdge[edge.From+" "+edge.To] = true
+		sites := edge.Sites
+		if len(sites) == 0 && edge.File != "" {
+			sites = []model.CallSite{{File: edge.File, Line: edge.Line, Column: edge.Column}}
+		}
+		source.callers[edge.To] = append(source.callers[edge.To], callerRef{key: edge.From, edge: edge, sites: sites})
+	}
+	// Calls outside the analyzed source have no call graph edge, so how they are made
+	// is taken from the calls recorded on the caller
+	for _, fn := range ctree.Functions {
+		key := u.getFunctionKey(fn)
+		for _, callee := range fn.CallsTo {
+			if hasEdge[key+" "+callee] {
+				continue
+			}
+			hasEdge[key+" "+callee] = true
+			source.callers[callee] = append(source.callers[callee], callerRef{
+				key:   key,
+				edge:  model.CallEdge{From: key, To: callee, Dynamic: slices.Contains(fn.DynamicCalls, callee)},
+				field: slices.Contains(fn.FieldCalls, callee),
+			})
+		}
+	}
+	for _, callers := range source.callers {
+		sort.SliceStable(callers, func(i, j int) bool { return callers[i].key < callers[j].key })
+	}
+
+	var nodes []model.CallTreeNode
+	for _, target := range targets {
+		visited := make(map[string]bool)
+		nodes = append(nodes, u.buildCallerNodeRecursive(target, source, visited, 0, 10)) // max depth 10
+	}
+	return nodes
+}
+
+// buildCallerNodeRecursive builds the node of a function with its callers as children,
+// marking a caller already on the path as recursive
+func (u *goPureProjectGenerateUsecase) buildCallerNodeRecursive(key string, source *callerTreeSource, visited map[string]bool, depth int, maxDepth int) model.CallTreeNode {
+	node := u.newCallerNode(key, source)
+
+	if visited[key] {
+		node.IsRecursive = true
+		return node
+	}
+	if depth >= maxDepth {
+		// The callers above are still counted so the cut is visible
+		node.Truncated = u.countCallers(key, source, visited)
+		return node
+	}
+
+	visited[key] = true
+	defer func() { visited[key] = false }()
+
+	for _, caller := range source.callers[key] {
+		callerNode := u.buildCallerNodeRecursive(caller.key, source, visited, depth+1, maxDepth)
+		callerNode.IsDynamic = caller.edge.Dynamic
+		callerNode.IsField = caller.field
+		callerNode.Via = caller.edge.Via
+		callerNode.CallKind = caller.edge.CallKind
+		callerNode.CallSites = caller.sites
+		node.Children = append(node.Children, callerNode)
+	}
+
+	return node
+}
+
+// countCallers returns the number of functions calling key directly or indirectly,
+// leaving out those already on the path
+func (u *goPureProjectGenerateUsecase) countCallers(key string, source *callerTreeSource, visited map[string]bool) int {
+	seen := map[string]bool{key: true}
+	queue := []string{key}
+	count := 0
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, caller := range source.callers[current] {
+			if seen[caller.key] || visited[caller.key] {
+				continue
+			}
+			seen[caller.key] = true
+			count++
+			queue = append(queue, caller.key)
+		}
+	}
+	return count
+}
+
+// newCallerNode creates the node of an analyzed function, or a placeholder for a function
+// outside the analyzed source
+func (u *goPureProjectGenerateUsecase) newCallerNode(key string, source *callerTreeSource) model.CallTreeNode {
+	if fn, ok := source.functions[key]; ok {
+		return model.CallTreeNode{
+			Title:       u.buildFunctionSignature(fn),
+			Name:        fn.Name,
+			Package:     fn.Package,
+			PackagePath: fn.PackagePath,
+			File:        fn.File,
+			Line:        fn.Line,
+			Kind:        fn.Kind,
+			Receiver:    fn.Receiver,
+			Constraint:  fn.Constraint,
+			TypeParams:  fn.TypeParams,
+			Parameters:  fn.Parameters,
+			ReturnTypes: fn.ReturnTypes,
+			Origin:      originInternal,
+			Module:      fn.Module,
+			IsEntry:     source.entryPoints[key],
+		}
+	}
+
+	// The longest import path the key starts with is the package of the function
+	node := model.CallTreeNode{
+		Title: key + "()",
+		Name:  key,
+		Kind:  "external",
+	}
+	for importPath, name := range source.imports {
+		if strings.HasPrefix(key, importPath+".") && len(importPath) > len(node.PackagePath) {
+			node.Package = name
+			node.PackagePath = importPath
+		}
+	}
+	if node.PackagePath != "" {
+		node.Title = node.Package + key[len(node.PackagePath):] + "()"
+		node.Origin, node.Module, node.ModuleVersion = u.classifyPackagePath(node.PackagePath, source.modules)
+	}
+	return node
+}
@@ -0,0 +1,186 @@
+package golang
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ryo-arima/ctree/pkg/entity/model"
+	"github.com/ryo-arima/ctree/pkg/entity/request"
+)
+
+// formatCallerTree renders a caller tree as one line per node, indented by depth,
+// with the node title and its markers
+func formatCallerTree(node model.CallTreeNode, depth int, lines *[]string) {
+	line := strings.Repeat("  ", depth) + node.Title
+	if node.IsEntry {
+		line += " [entry]"
+	}
+	if node.IsRecursive {
+		line += " [recursive]"
+	}
+	if node.Kind == "external" {
+		line += " [" + node.Origin + "]"
+	}
+	*lines = append(*lines, line)
+	for _, child := range node.Children {
+		formatCallerTree(child, depth+1, lines)
+	}
+}
+
+func TestBuildCallerTree(t *testing.T) {
+	ctree := &model.CTree{
+		Functions: []model.Function{
+			{Name: "main", Package: "main", PackagePath: "example.com/app", CallsTo: []string{"example.com/app.run", "os.Exit"}},
+			{Name: "run", Package: "main", PackagePath: "example.com/app", CallsTo: []string{"example.com/app.walk"}},
+			{Name: "walk", Package: "main", PackagePath: "example.com/app", CallsTo: []string{"example.com/app.walk", "os.Exit"}},
+		},
+		EntryPoints: []model.Function{{Name: "main", Package: "main", PackagePath: "example.com/app"}},
+		CallGraph: []model.CallEdge{
+			{From: "example.com/app.main", To: "example.com/app.run"},
+			{From: "example.com/app.run", To: "example.com/app.walk"},
+			{From: "example.com/app.walk", To: "example.com/app.walk"},
+		},
+		FileImports: map[string]map[string]string{"main.go": {"os": "os"}},
+	}
+
+	tests := []struct {
+		target string
+		want   []string
+	}{
+		{
+			target: "example.com/app.run",
+			want: []string{
+				"func run()",
+				"  func main() [entry]",
+			},
+		},
+		{
+			target: "example.com/app.walk",
+			want: []string{
+				"func walk()",
+				"  func run()",
+				"    func main() [entry]",
+				"  func walk() [recursive]",
+			},
+		},
+		{
+			// Calls outside the analyzed source are found through CallsTo
+			target: "os.Exit",
+			want: []string{
+				"os.Exit() [stdlib]",
+				"  func main() [entry]",
+				"  func walk()",
+				"    func run()",
+				"      func main() [entry]",
+				"    func walk() [recursive]",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			nodes := NewGoPureProjectGenerateUsecase(nil).BuildCallerTree(ctree, []string{tt.target})
+			if len(nodes) != 1 {
+				t.Fatalf("got %d caller trees, want 1", len(nodes))
+			}
+			var lines []string
+			formatCallerTree(nodes[0], 0, &lines)
+			if got, want := strings.Join(lines, "\n"), strings.Join(tt.want, "\n"); got != want {
+				t.Errorf("caller tree:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestBuildCallerTreeMarksExternalCallees(t *testing.T) {
+	t.Run("modules", func(t *testing.T) {
+		ctree := analyzeFixture(t, "origins", request.GenerateRequest{})
+		nodes := NewGoPureProjectGenerateUsecase(nil).BuildCallerTree(ctree, []string{"github.com/acme/lib.Run"})
+		if len(nodes) != 1 {
+			t.Fatalf("got %d caller trees, want 1", len(nodes))
+		}
+		node := nodes[0]
+		if node.Origin != "third_party" || node.Module != "github.com/acme/lib" || node.ModuleVersion != "v1.2.3" {
+			t.Errorf("origin %q, module %q@%q; want third_party, github.com/acme/lib@v1.2.3", node.Origin, node.Module, node.ModuleVersion)
+		}
+	})
+
+	ctree := analyzeFixture(t, "fields", request.GenerateRequest{})
+	tests := []struct {
+		target  string
+		field   bool
+		dynamic bool
+	}{
+		{"example.com/fields.server.handler", true, false},
+		{"example.com/fields.Shape.Area", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			nodes := NewGoPureProjectGenerateUsecase(nil).BuildCallerTree(ctree, []string{tt.target})
+			if len(nodes) != 1 || len(nodes[0].Children) != 1 {
+				t.Fatalf("got %+v, want %s with one caller", nodes, tt.target)
+			}
+			caller := nodes[0].Children[0]
+			if caller.IsField != tt.field || caller.IsDynamic != tt.dynamic {
+				t.Errorf("field %v, dynamic %v; want field %v, dynamic %v", caller.IsField, caller.IsDynamic, tt.field, tt.dynamic)
+			}
+		})
+	}
+}
+
+func TestBuildCallerTreeRecordsCallSites(t *testing.T) {
+	ctree := analyzeFixture(t, "sites", request.GenerateRequest{})
+	nodes := NewGoPureProjectGenerateUsecase(nil).BuildCallerTree(ctree, []string{"example.com/sites.helper"})
+	if len(nodes) != 1 || len(nodes[0].Children) != 1 {
+		t.Fatalf("got %+v, want helper with one caller", nodes)
+	}
+	if sites := nodes[0].Children[0].CallSites; len(sites) != 2 {
+		t.Errorf("caller has %d call sites, want 2", len(sites))
+	}
+}
+
+// newChainCTree returns a call tree where each of n functions f0..fn-1 calls the next one
+func newChainCTree(n int) *model.CTree {
+	ctree := &model.CTree{}
+	for i := 0; i < n; i++ {
+		ctree.Functions = append(ctree.Functions, model.Function{Name: fmt.Sprintf("f%d", i), PackagePath: "example.com/chain"})
+		if i > 0 {
+			ctree.CallGraph = append(ctree.CallGraph, model.CallEdge{
+				From: fmt.Sprintf("example.com/chain.f%d", i-1),
+				To:   fmt.Sprintf("example.com/chain.f%d", i),
+			})
+		}
+	}
+	return ctree
+}
+
+func TestBuildCallerTreeMarksDepthLimit(t *testing.T) {
+	tests := []struct {
+		name          string
+		functions     int
+		wantTopName   string
+		wantTruncated int
+	}{
+		{"chain within the limit", 5, "f0", 0},
+		{"chain at the limit", 11, "f0", 0},
+		{"chain beyond the limit", 14, "f3", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctree := newChainCTree(tt.functions)
+			target := fmt.Sprintf("example.com/chain.f%d", tt.functions-1)
+			nodes := NewGoPureProjectGenerateUsecase(nil).BuildCallerTree(ctree, []string{target})
+			if len(nodes) != 1 {
+				t.Fatalf("got %d caller trees, want 1", len(nodes))
+			}
+
+			top := nodes[0]
+			for len(top.Children) > 0 {
+				top = top.Children[0]
+			}
+			if top.Name != tt.wantTopName || top.Truncated != tt.wantTruncated {
+				t.Errorf("topmost caller %s with %d more, want %s with %d more", top.Name, top.Truncated, tt.wantTopName, tt.wantTruncated)
+			}
+		})
+	}
+}
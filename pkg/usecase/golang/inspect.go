@@ -0,0 +1,520 @@
+package golang
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ryo-arima/ctree/pkg/entity/model"
+	"github.com/ryo-arima/ctree/pkg/entity/request"
+	"github.com/ryo-arima/ctree/pkg/repository/golang"
+)
+
+// ListClasses extracts every package-level type declaration of the source
+func (u *goPureProjectGenerateUsecase) ListClasses(req request.GenerateRequest) ([]model.Class, error) {
+	packages, err := u.loadPackages(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var classes []model.Class
+	for _, pkg := range packages {
+		for i, file := range pkg.Files {
+			classes = append(classes, u.repo.ExtractTypes(file, pkg, u.getRelativePath(pkg.FilePaths[i]))...)
+		}
+	}
+	return classes, nil
+}
+
+// ListImplementations matches the named types of the source against its interfaces
+func (u *goPureProjectGenerateUsecase) ListImplementations(req request.GenerateRequest) ([]model.Implementation, error) {
+	packages, err := u.loadPackages(req)
+	if err != nil {
+		return nil, err
+	}
+	return u.findInterfaceImplementations(packages), nil
+}
+
+// declaredType is a package-level named type together with the package declaring it
+type declaredType struct {
+	obj *types.TypeName
+	pkg *golang.GoPackage
+}
+
+// findInterfaceImplementations returns every pair of a non-interface type and an interface
+// of the analyzed packages where the method set of the type, or of a pointer to it,
+// satisfies the interface. Empty interfaces, constraint interfaces and generic types are skipped.
+func (u *goPureProjectGenerateUsecase) findInterfaceImplementations(packages []*golang.GoPackage) []model.Implementation {
+	var interfaces, concrete []declaredType
+	for _, pkg := range packages {
+		if pkg.Types == nil {
+			continue
+		}
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			typeName, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || typeName.IsAlias() {
+				continue
+			}
+			named, ok := typeName.Type().(*types.Named)
+			if !ok || named.TypeParams().Len() > 0 {
+				continue
+			}
+			if iface, ok := named.Underlying().(*types.Interface); ok {
+				if iface.NumMethods() > 0 && iface.IsMethodSet() {
+					interfaces = append(interfaces, declaredType{obj: typeName, pkg: pkg})
+				}
+				continue
+			}
+			concrete = append(concrete, declaredType{obj: typeName, pkg: pkg})
+		}
+	}
+
+	var implementations []model.Implementation
+	for _, iface := range interfaces {
+		ifaceType := iface.obj.Type().Underlying().(*types.Interface)
+		ifacePos := iface.pkg.Fset.Position(iface.obj.Pos())
+
+		for _, impl := range concrete {
+			// Methods with pointer receivers are only in the method set of *T
+			pointer := false
+			if !types.Implements(impl.obj.Type(), ifaceType) {
+				if !types.Implements(types.NewPointer(impl.obj.Type()), ifaceType) {
+					continue
+				}
+				pointer = true
+			}
+
+			implPos := impl.pkg.Fset.Position(impl.obj.Pos())
+			implementations = append(implementations, model.Implementation{
+				Interface:            iface.obj.Name(),
+				InterfacePackage:     iface.obj.Pkg().Name(),
+				InterfacePackagePath: iface.obj.Pkg().Path(),
+				InterfaceFile:        u.getRelativePath(ifacePos.Filename),
+				InterfaceLine:        ifacePos.Line,
+				Type:                 impl.obj.Name(),
+				Package:              impl.obj.Pkg().Name(),
+				PackagePath:          impl.obj.Pkg().Path(),
+				File:                 u.getRelativePath(implPos.Filename),
+				Line:                 implPos.Line,
+				Pointer:              pointer,
+			})
+		}
+	}
+
+	return implementations
+}
+
+// ListVariables extracts every package-level variable and constant of the source
+// together with the functions referencing them
+func (u *goPureProjectGenerateUsecase) ListVariables(req request.GenerateRequest) ([]model.Variable, error) {
+	packages, err := u.loadPackages(req)
+	if err != nil {
+		return nil, err
+	}
+
+	references := u.collectVariableReferences(packages)
+
+	var variables []model.Variable
+	for _, pkg := range packages {
+		for i, file := range pkg.Files {
+			for _, variable := range u.repo.ExtractVariables(file, pkg, u.getRelativePath(pkg.FilePaths[i])) {
+				variable.ReferencedBy = references[variable.PackagePath+"."+variable.Name]
+				variables = append(variables, variable)
+			}
+		}
+	}
+	return variables, nil
+}
+
+// collectVariableReferences finds the functions reading or writing package-level variables
+// and constants, keyed by the import path and name of the declaration. References inside closures count for
+// the enclosing function, and references in package-level initializers count for the variable or
+// constant being initialized. Each function is listed once per access kind.
+func (u *goPureProjectGenerateUsecase) collectVariableReferences(packages []*golang.GoPackage) map[string][]model.VariableReference {
+	references := make(map[string][]model.VariableReference)
+
+	for _, pkg := range packages {
+		if pkg.Info == nil {
+			continue
+		}
+		for i, file := range pkg.Files {
+			relPath := u.getRelativePath(pkg.FilePaths[i])
+			for _, decl := range file.Decls {
+				switch decl := decl.(type) {
+				case *ast.FuncDecl:
+					fn, ok := pkg.Info.Defs[decl.Name].(*types.Func)
+					if !ok || decl.Body == nil {
+						continue
+					}
+					u.collectReferences(references, u.getObjectKey(fn), decl.Body, pkg, relPath)
+				case *ast.GenDecl:
+					for _, spec := range decl.Specs {
+						valueSpec, ok := spec.(*ast.ValueSpec)
+						if !ok {
+							continue
+						}
+						for j, value := range valueSpec.Values {
+							// var a, b = f() initializes every name from one value
+							names := valueSpec.Names
+							if len(valueSpec.Values) == len(names) {
+								names = names[j : j+1]
+							}
+							for _, name := range names {
+								u.collectReferences(references, pkg.Path+"."+name.Name, value, pkg, relPath)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return references
+}
+
+// collectReferences adds the package-level variables and constants referenced within node
+// to references on behalf of owner
+func (u *goPureProjectGenerateUsecase) collectReferences(references map[string][]model.VariableReference, owner string, node ast.Node, pkg *golang.GoPackage, relPath string) {
+	written := make(map[*ast.Ident][]string) // assignment target -> access kinds
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			// Compound assignments such as x += 1 read the target before writing it
+			accesses := []string{"write"}
+			if stmt.Tok != token.ASSIGN && stmt.Tok != token.DEFINE {
+				accesses = []string{"read", "write"}
+			}
+			for _, lhs := range stmt.Lhs {
+				u.markWritten(lhs, accesses, written)
+			}
+		case *ast.IncDecStmt:
+			u.markWritten(stmt.X, []string{"read", "write"}, written)
+		case *ast.RangeStmt:
+			if stmt.Tok == token.ASSIGN {
+				u.markWritten(stmt.Key, []string{"write"}, written)
+				u.markWritten(stmt.Value, []string{"write"}, written)
+			}
+		}
+		return true
+	})
+
+	seen := make(map[string]bool)
+	ast.Inspect(node, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		variable := pkg.Info.Uses[ident]
+		switch variable.(type) {
+		case *types.Var, *types.Const:
+		default:
+			return true
+		}
+		if variable.Pkg() == nil || variable.Parent() != variable.Pkg().Scope() {
+			return true
+		}
+
+		accesses := written[ident]
+		if accesses == nil {
+			accesses = []string{"read"}
+		}
+		key := variable.Pkg().Path() + "." + variable.Name()
+		for _, access := range accesses {
+			if seen[key+" "+access] {
+				continue
+			}
+			seen[key+" "+access] = true
+
+			references[key] = append(references[key], model.VariableReference{
+				Function: owner,
+				Access:   access,
+				File:     relPath,
+				Line:     pkg.Fset.Position(ident.Pos()).Line,
+			})
+		}
+		return true
+	})
+}
+
+// markWritten records the access kinds of the identifiers an assignment target writes through,
+// such as config for config.Name = x or counts for counts[key]++
+func (u *goPureProjectGenerateUsecase) markWritten(expr ast.Expr, accesses []string, written map[*ast.Ident][]string) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		written[e] = accesses
+	case *ast.SelectorExpr:
+		written[e.Sel] = accesses
+		u.markWritten(e.X, accesses, written)
+	case *ast.IndexExpr:
+		u.markWritten(e.X, accesses, written)
+	case *ast.StarExpr:
+		u.markWritten(e.X, accesses, written)
+	case *ast.ParenExpr:
+		u.markWritten(e.X, accesses, written)
+	}
+}
+
+// ListImports reports the imports of the source per file, per package and per import path,
+// telling the standard library, internal packages and third-party modules apart
+func (u *goPureProjectGenerateUsecase) ListImports(req request.GenerateRequest) (*model.ImportReport, error) {
+	packages, err := u.loadPackages(req)
+	if err != nil {
+		return nil, err
+	}
+
+	modules, err := u.repo.LoadModuleInfo(req.SourcePath)
+	if err != nil {
+		// Without module information, only the standard library can be told apart
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	report := &model.ImportReport{Origins: make(map[string]int)}
+	summaries := make(map[string]*model.ImportSummary) // import path -> summary
+	var paths []string
+	for _, pkg := range packages {
+		pkgImports := model.PackageImports{
+			Package:     pkg.Name,
+			PackagePath: pkg.Path,
+			Files:       len(pkg.Files),
+		}
+		importedBy := make(map[string]bool) // import paths already counted for this package
+
+		for i, file := range pkg.Files {
+			fileImports := model.FileImports{
+				File:        u.getRelativePath(pkg.FilePaths[i]),
+				Package:     pkg.Name,
+				PackagePath: pkg.Path,
+			}
+
+			for _, imp := range u.repo.ExtractImportDecls(file, pkg.Fset) {
+				imp.Origin, imp.Module, imp.ModuleVersion = u.classifyPackagePath(imp.Path, modules)
+				fileImports.Imports = append(fileImports.Imports, imp)
+
+				summary, ok := summaries[imp.Path]
+				if !ok {
+					summary = &model.ImportSummary{
+						Path:          imp.Path,
+						Origin:        imp.Origin,
+						Module:        imp.Module,
+						ModuleVersion: imp.ModuleVersion,
+					}
+					summaries[imp.Path] = summary
+					paths = append(paths, imp.Path)
+					report.Origins[imp.Origin]++
+				}
+				summary.Files++
+				switch {
+				case imp.Blank:
+					summary.Blank++
+				case imp.Dot:
+					summary.Dot++
+				case imp.Name != "" && !slices.Contains(summary.Aliases, imp.Name):
+					summary.Aliases = append(summary.Aliases, imp.Name)
+				}
+
+				if importedBy[imp.Path] {
+					continue
+				}
+				importedBy[imp.Path] = true
+				summary.Packages++
+				pkgImports.Imports = append(pkgImports.Imports, imp.Path)
+				switch imp.Origin {
+				case originStdlib:
+					pkgImports.Stdlib++
+				case originThirdParty:
+					pkgImports.ThirdParty++
+				default:
+					pkgImports.Internal++
+				}
+			}
+
+			report.Files = append(report.Files, fileImports)
+		}
+
+		sort.Strings(pkgImports.Imports)
+		report.Packages = append(report.Packages, pkgImports)
+	}
+
+	// The most imported paths come first
+	sort.SliceStable(paths, func(i, j int) bool {
+		a, b := summaries[paths[i]], summaries[paths[j]]
+		if a.Files != b.Files {
+			return a.Files > b.Files
+		}
+		return a.Path < b.Path
+	})
+	for _, importPath := range paths {
+		report.Paths = append(report.Paths, *summaries[importPath])
+	}
+
+	return report, nil
+}
+
+// SummarizeFunctions computes the size, fan-in and fan-out of every function of a call tree.
+// Only edges between analyzed functions count, so calls into the standard library or
+// unfollowed dependencies do not add to the fan-out.
+func (u *goPureProjectGenerateUsecase) SummarizeFunctions(ctree *model.CTree) []model.FunctionSummary {
+	callers, callees := u.collectCallPartners(ctree)
+
+	summaries := make([]model.FunctionSummary, 0, len(ctree.Functions))
+	for _, fn := range ctree.Functions {
+		summaries = append(summaries, u.summarizeFunction(fn, callers, callees))
+	}
+
+	return summaries
+}
+
+// collectCallPartners returns the distinct callers and callees of every function of a call tree
+func (u *goPureProjectGenerateUsecase) collectCallPartners(ctree *model.CTree) (callers, callees map[string]map[string]bool) {
+	callers = make(map[string]map[string]bool) // callee key -> caller keys
+	callees = make(map[string]map[string]bool) // caller key -> callee keys
+	for _, edge := range ctree.CallGraph {
+		if callers[edge.To] == nil {
+			callers[edge.To] = make(map[string]bool)
+		}
+		callers[edge.To][edge.From] = true
+		if callees[edge.From] == nil {
+			callees[edge.From] = make(map[string]bool)
+		}
+		callees[edge.From][edge.To] = true
+	}
+	return callers, callees
+}
+
+// summarizeFunction computes the summary of a function from the call partners collected by collectCallPartners
+func (u *goPureProjectGenerateUsecase) summarizeFunction(fn model.Function, callers, callees map[string]map[string]bool) model.FunctionSummary {
+	key := u.getFunctionKey(fn)
+	summary := model.FunctionSummary{
+		Key:         key,
+		Name:        fn.Name,
+		Kind:        fn.Kind,
+		Package:     fn.Package,
+		PackagePath: fn.PackagePath,
+		Receiver:    fn.Receiver,
+		Signature:   u.buildFunctionSignature(fn),
+		File:        fn.File,
+		Line:        fn.Line,
+		Exported:    u.isExportedFunction(fn),
+		FanIn:       len(callers[key]),
+		FanOut:      len(callees[key]),
+	}
+	if fn.EndLine > 0 {
+		summary.Lines = fn.EndLine - fn.Line + 1
+	}
+	return summary
+}
+
+// DescribeFunctions returns the functions of a call tree whose summary is accepted by match
+// with their direct callers and callees, and the entry points they are reachable from
+func (u *goPureProjectGenerateUsecase) DescribeFunctions(ctree *model.CTree, match func(summary model.FunctionSummary) bool) []model.FunctionDetail {
+	callers := make(map[string][]model.CallEdge) // callee key -> incoming edges
+	callees := make(map[string][]model.CallEdge) // caller key -> outgoing edges
+	for _, edge := range ctree.CallGraph {
+		callers[edge.To] = append(callers[edge.To], edge)
+		callees[edge.From] = append(callees[edge.From], edge)
+	}
+
+	// Functions reachable from each entry point
+	reachedFrom := make(map[string][]string) // function key -> entry point keys
+	for _, ep := range ctree.EntryPoints {
+		entryKey := u.getFunctionKey(ep)
+		visited := map[string]bool{entryKey: true}
+		queue := []string{entryKey}
+		for len(queue) > 0 {
+			key := queue[0]
+			queue = queue[1:]
+			reachedFrom[key] = append(reachedFrom[key], entryKey)
+			for _, edge := range callees[key] {
+				if !visited[edge.To] {
+					visited[edge.To] = true
+					queue = append(queue, edge.To)
+				}
+			}
+		}
+	}
+
+	analyzedPackages := make(map[string]bool)
+	for _, fn := range ctree.Functions {
+		analyzedPackages[fn.PackagePath] = true
+	}
+
+	callerKeys, calleeKeys := u.collectCallPartners(ctree)
+	var details []model.FunctionDetail
+	for _, fn := range ctree.Functions {
+		summary := u.summarizeFunction(fn, callerKeys, calleeKeys)
+		if !match(summary) {
+			continue
+		}
+		key := summary.Key
+		detail := model.FunctionDetail{
+			FunctionSummary: summary,
+			EndLine:         fn.EndLine,
+			Doc:             fn.Doc,
+			Reachable:       len(reachedFrom[key]) > 0,
+			ReachableFrom:   reachedFrom[key],
+		}
+
+		for _, edge := range callers[key] {
+			detail.Callers = append(detail.Callers, u.newFunctionCall(edge.From, edge))
+		}
+		sort.SliceStable(detail.Callers, func(i, j int) bool {
+			return detail.Callers[i].Function < detail.Callers[j].Function
+		})
+		called := make(map[string]bool)
+		for _, edge := range callees[key] {
+			// Platform variants are called through the key without their constraint
+			base, _, _ := strings.Cut(edge.To, "@")
+			called[edge.To] = true
+			called[base] = true
+			detail.Callees = append(detail.Callees, u.newFunctionCall(edge.To, edge))
+		}
+
+		// Callees without a call graph edge are outside the analyzed source, interface
+		// methods left unexpanded by static dispatch, or function-typed struct fields;
+		// builtins are left out
+		for _, callee := range fn.CallsTo {
+			if called[callee] || types.Universe.Lookup(callee) != nil {
+				continue
+			}
+			called[callee] = true
+			call := model.FunctionCall{
+				Function: callee,
+				Dynamic:  slices.Contains(fn.DynamicCalls, callee),
+				Field:    slices.Contains(fn.FieldCalls, callee),
+				External: true,
+			}
+			for packagePath := range analyzedPackages {
+				if strings.HasPrefix(callee, packagePath+".") {
+					call.External = false
+					break
+				}
+			}
+			detail.Callees = append(detail.Callees, call)
+		}
+
+		details = append(details, detail)
+	}
+
+	return details
+}
+
+// newFunctionCall describes the calls of a call graph edge from the side of function
+func (u *goPureProjectGenerateUsecase) newFunctionCall(function string, edge model.CallEdge) model.FunctionCall {
+	call := model.FunctionCall{
+		Function: function,
+		CallKind: edge.CallKind,
+		Dynamic:  edge.Dynamic,
+		Sites:    edge.Sites,
+	}
+	// Edges list their sites only when there are several
+	if len(call.Sites) == 0 && edge.File != "" {
+		call.Sites = []model.CallSite{{File: edge.File, Line: edge.Line, Column: edge.Column}}
+	}
+	return call
+}
@@ -0,0 +1,379 @@
+package golang
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/ryo-arima/ctree/pkg/entity/model"
+	"github.com/ryo-arima/ctree/pkg/entity/request"
+)
+
+func TestListClasses(t *testing.T) {
+	req := request.GenerateRequest{SourcePath: filepath.Join("testdata", "classes"), Recursive: true}
+	classes, err := NewGoPureProjectGenerateUsecase(nil).ListClasses(req)
+	if err != nil {
+		t.Fatalf("ListClasses failed: %v", err)
+	}
+	byName := map[string]model.Class{}
+	for _, class := range classes {
+		byName[class.Name] = class
+	}
+
+	tests := []struct {
+		name       string
+		kind       string
+		underlying string
+		fields     []model.Field
+		embeds     []string
+		methods    []model.ClassMethod
+	}{
+		{
+			name:   "Base",
+			kind:   "struct",
+			fields: []model.Field{{Name: "ID", Type: "int", Tag: `json:"id"`}},
+			methods: []model.ClassMethod{
+				{Name: "Describe", Signature: "() string"},
+				{Name: "Reset", Signature: "()", Pointer: true},
+			},
+		},
+		{
+			name: "Service",
+			kind: "struct",
+			fields: []model.Field{
+				{Name: "Base", Type: "*Base", Embedded: true},
+				{Name: "Writer", Type: "io.Writer", Embedded: true},
+				{Name: "Name", Type: "string", Tag: `json:"name"`},
+				{Name: "Owner", Type: "string", Tag: `json:"name"`},
+			},
+			embeds: []string{"*Base", "io.Writer"},
+			methods: []model.ClassMethod{
+				{Name: "Describe", Signature: "() string", Via: "Base"},
+				{Name: "Reset", Signature: "()", Pointer: true, Via: "Base"},
+				{Name: "Run", Signature: "() error", Pointer: true},
+				{Name: "Write", Signature: "(p []byte) (n int, err error)", Via: "Writer"},
+			},
+		},
+		{
+			name:   "Runner",
+			kind:   "interface",
+			embeds: []string{"io.Closer"},
+			methods: []model.ClassMethod{
+				{Name: "Close", Signature: "() error"},
+				{Name: "Run", Signature: "() error"},
+			},
+		},
+		{name: "Count", kind: "named", underlying: "int"},
+		{name: "Alias", kind: "alias", underlying: "Service"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class, ok := byName[tt.name]
+			if !ok {
+				t.Fatalf("type %s not listed", tt.name)
+			}
+			if class.Kind != tt.kind || class.Underlying != tt.underlying {
+				t.Errorf("kind = %q underlying = %q, want %q %q", class.Kind, class.Underlying, tt.kind, tt.underlying)
+			}
+			if !reflect.DeepEqual(class.Fields, tt.fields) {
+				t.Errorf("fields = %+v, want %+v", class.Fields, tt.fields)
+			}
+			if !reflect.DeepEqual(class.Embeds, tt.embeds) {
+				t.Errorf("embeds = %v, want %v", class.Embeds, tt.embeds)
+			}
+			if !reflect.DeepEqual(class.Methods, tt.methods) {
+				t.Errorf("methods = %+v, want %+v", class.Methods, tt.methods)
+			}
+		})
+	}
+
+	if doc := byName["Base"].Doc; doc != "Base carries the shared state" {
+		t.Errorf("Base doc = %q", doc)
+	}
+}
+
+func TestListImplementations(t *testing.T) {
+	req := request.GenerateRequest{SourcePath: filepath.Join("testdata", "implements"), Recursive: true}
+	implementations, err := NewGoPureProjectGenerateUsecase(nil).ListImplementations(req)
+	if err != nil {
+		t.Fatalf("ListImplementations failed: %v", err)
+	}
+
+	var got []string
+	for _, impl := range implementations {
+		entry := impl.Type + " " + impl.Interface
+		if impl.Pointer {
+			entry = "*" + entry
+		}
+		got = append(got, entry)
+	}
+	sort.Strings(got)
+
+	// Closer needs *File; Stream embeds *File and gets Close without a pointer.
+	// Number, Any and the generic Box are skipped.
+	want := []string{
+		"*Buffered Closer",
+		"*Buffered ReadCloser",
+		"*File Closer",
+		"*File ReadCloser",
+		"Buffered Reader",
+		"File Reader",
+		"Stream Closer",
+		"Stream ReadCloser",
+		"Stream Reader",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("implementations:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestListVariables(t *testing.T) {
+	req := request.GenerateRequest{SourcePath: filepath.Join("testdata", "variables"), Recursive: true}
+	variables, err := NewGoPureProjectGenerateUsecase(nil).ListVariables(req)
+	if err != nil {
+		t.Fatalf("ListVariables failed: %v", err)
+	}
+	byName := map[string]model.Variable{}
+	for _, variable := range variables {
+		byName[variable.Name] = variable
+	}
+
+	tests := []struct {
+		name     string
+		kind     string
+		typ      string
+		value    string
+		constant string
+		iota     bool
+		exported bool
+	}{
+		{name: "base", kind: "var", typ: "int", value: "10"},
+		{name: "Double", kind: "const", typ: "untyped int", value: "Limit * 2", exported: true},
+		{name: "Debug", kind: "const", typ: "Level", value: "iota", constant: "0", iota: true, exported: true},
+		{name: "Info", kind: "const", typ: "Level", value: "iota", constant: "1", iota: true, exported: true},
+		{name: "counter", kind: "var", typ: "int"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, ok := byName[tt.name]
+			if !ok {
+				t.Fatalf("%s not listed", tt.name)
+			}
+			got := []interface{}{v.Kind, v.Type, v.Value, v.Constant, v.Iota, v.Exported}
+			want := []interface{}{tt.kind, tt.typ, tt.value, tt.constant, tt.iota, tt.exported}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("kind, type, value, constant, iota, exported = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestListVariablesReferences(t *testing.T) {
+	req := request.GenerateRequest{SourcePath: filepath.Join("testdata", "variables"), Recursive: true, MaxDepth: 10}
+	variables, err := NewGoPureProjectGenerateUsecase(nil).ListVariables(req)
+	if err != nil {
+		t.Fatalf("ListVariables failed: %v", err)
+	}
+
+	got := make(map[string][]string)
+	for _, variable := range variables {
+		var refs []string
+		for _, ref := range variable.ReferencedBy {
+			refs = append(refs, ref.Function+" "+ref.Access)
+		}
+		sort.Strings(refs)
+		got[variable.Name] = refs
+	}
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"base", []string{"example.com/variables.derived read", "example.com/variables.inc read"}},
+		{"derived", []string{"example.com/variables.main read"}},
+		{"Limit", []string{"example.com/variables.Double read"}},
+		{"Double", []string{"example.com/variables.main read"}},
+		{"counter", []string{"example.com/variables.inc read", "example.com/variables.inc write"}},
+		{"total", []string{"example.com/variables.inc read", "example.com/variables.inc write"}},
+		{"Debug", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(got[tt.name], tt.want) {
+				t.Errorf("referenced by: got %v, want %v", got[tt.name], tt.want)
+			}
+		})
+	}
+}
+
+func TestListImports(t *testing.T) {
+	req := request.GenerateRequest{SourcePath: filepath.Join("testdata", "imports"), Recursive: true, MaxDepth: 10}
+	report, err := NewGoPureProjectGenerateUsecase(nil).ListImports(req)
+	if err != nil {
+		t.Fatalf("ListImports failed: %v", err)
+	}
+
+	wantPaths := []model.ImportSummary{
+		{Path: "example.com/imports/store", Origin: "internal", Module: "example.com/imports", Files: 2, Packages: 1, Dot: 1},
+		{Path: "fmt", Origin: "stdlib", Files: 2, Packages: 1},
+		{Path: "strings", Origin: "stdlib", Files: 2, Packages: 2, Aliases: []string{"str"}},
+		{Path: "embed", Origin: "stdlib", Files: 1, Packages: 1, Blank: 1},
+		{Path: "github.com/acme/lib", Origin: "third_party", Module: "github.com/acme/lib", ModuleVersion: "v1.2.3", Files: 1, Packages: 1},
+	}
+	if !reflect.DeepEqual(report.Paths, wantPaths) {
+		t.Errorf("paths:\n got %+v\nwant %+v", report.Paths, wantPaths)
+	}
+
+	wantOrigins := map[string]int{"internal": 1, "stdlib": 3, "third_party": 1}
+	if !reflect.DeepEqual(report.Origins, wantOrigins) {
+		t.Errorf("origins = %v, want %v", report.Origins, wantOrigins)
+	}
+
+	tests := []struct {
+		packagePath string
+		files       int
+		imports     []string
+		counts      [3]int // stdlib, internal, third-party
+	}{
+		{"example.com/imports", 2, []string{"embed", "example.com/imports/store", "fmt", "github.com/acme/lib", "strings"}, [3]int{3, 1, 1}},
+		{"example.com/imports/store", 1, []string{"strings"}, [3]int{1, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.packagePath, func(t *testing.T) {
+			for _, pkg := range report.Packages {
+				if pkg.PackagePath != tt.packagePath {
+					continue
+				}
+				if pkg.Files != tt.files || !reflect.DeepEqual(pkg.Imports, tt.imports) {
+					t.Errorf("files = %d imports = %v, want %d %v", pkg.Files, pkg.Imports, tt.files, tt.imports)
+				}
+				if counts := [3]int{pkg.Stdlib, pkg.Internal, pkg.ThirdParty}; counts != tt.counts {
+					t.Errorf("stdlib, internal, third-party = %v, want %v", counts, tt.counts)
+				}
+				return
+			}
+			t.Fatalf("package %s not reported", tt.packagePath)
+		})
+	}
+}
+
+func TestSummarizeFunctions(t *testing.T) {
+	ctree := analyzeFixture(t, "calls", request.GenerateRequest{})
+	u := &goPureProjectGenerateUsecase{}
+	byKey := map[string]model.FunctionSummary{}
+	for _, summary := range u.SummarizeFunctions(ctree) {
+		byKey[summary.Key] = summary
+	}
+
+	tests := []struct {
+		key    string
+		fanIn  int
+		fanOut int
+		lines  int
+	}{
+		{"example.com/calls.main", 0, 3, 5},
+		{"example.com/calls.server.run", 1, 1, 3},
+		{"example.com/calls/store.Store.Save", 1, 1, 3},
+		{"example.com/calls/store.Store.log", 1, 0, 1},
+		{"example.com/calls/other.Save", 0, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			summary, ok := byKey[tt.key]
+			if !ok {
+				t.Fatalf("%s not summarized", tt.key)
+			}
+			if summary.FanIn != tt.fanIn || summary.FanOut != tt.fanOut || summary.Lines != tt.lines {
+				t.Errorf("fan-in, fan-out, lines = %d %d %d, want %d %d %d", summary.FanIn, summary.FanOut, summary.Lines, tt.fanIn, tt.fanOut, tt.lines)
+			}
+		})
+	}
+}
+
+func TestDescribeFunctions(t *testing.T) {
+	ctree := analyzeFixture(t, "calls", request.GenerateRequest{})
+	u := &goPureProjectGenerateUsecase{}
+
+	tests := []struct {
+		key           string
+		callers       []model.FunctionCall
+		callees       []model.FunctionCall
+		reachableFrom []string
+	}{
+		{
+			key:           "example.com/calls/store.Store.Save",
+			callers:       []model.FunctionCall{{Function: "example.com/calls.server.run", CallKind: "call", Sites: []model.CallSite{{File: "testdata/calls/main.go", Line: 10, Column: 14}}}},
+			callees:       []model.FunctionCall{{Function: "example.com/calls/store.Store.log", CallKind: "call", Sites: []model.CallSite{{File: "testdata/calls/store/store.go", Line: 10, Column: 7}}}},
+			reachableFrom: []string{"example.com/calls.main"},
+		},
+		{
+			key: "example.com/calls/other.Save",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			details := u.DescribeFunctions(ctree, func(summary model.FunctionSummary) bool { return summary.Key == tt.key })
+			if len(details) != 1 {
+				t.Fatalf("got %d functions, want 1", len(details))
+			}
+			detail := details[0]
+			if !reflect.DeepEqual(detail.Callers, tt.callers) {
+				t.Errorf("callers: got %+v, want %+v", detail.Callers, tt.callers)
+			}
+			if !reflect.DeepEqual(detail.Callees, tt.callees) {
+				t.Errorf("callees: got %+v, want %+v", detail.Callees, tt.callees)
+			}
+			if detail.Reachable != (len(tt.reachableFrom) > 0) || !reflect.DeepEqual(detail.ReachableFrom, tt.reachableFrom) {
+				t.Errorf("reachable from: got %t %v, want %v", detail.Reachable, detail.ReachableFrom, tt.reachableFrom)
+			}
+		})
+	}
+}
+
+func TestDescribeFunctionsMarksCallees(t *testing.T) {
+	tests := []struct {
+		fixture string
+		req     request.GenerateRequest
+		want    []model.FunctionCall
+	}{
+		{
+			fixture: "fields",
+			want: []model.FunctionCall{
+				{Function: "example.com/fields.Shape.Area", Dynamic: true},
+				{Function: "example.com/fields.main.func1", CallKind: "ref"},
+				{Function: "example.com/fields.server.handler", Field: true},
+			},
+		},
+		{
+			fixture: "platforms",
+			req:     request.GenerateRequest{AllPlatforms: true},
+			want: []model.FunctionCall{
+				{Function: "example.com/platforms.open@linux", CallKind: "call"},
+				{Function: "example.com/platforms.open@windows", CallKind: "call"},
+				{Function: "example.com/platforms.tune@!amd64", CallKind: "call"},
+				{Function: "example.com/platforms.tune@(linux||darwin)&&amd64", CallKind: "call"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			ctree := analyzeFixture(t, tt.fixture, tt.req)
+			u := &goPureProjectGenerateUsecase{}
+			details := u.DescribeFunctions(ctree, func(summary model.FunctionSummary) bool { return summary.Name == "main" })
+			if len(details) != 1 {
+				t.Fatalf("got %d functions named main, want 1", len(details))
+			}
+
+			got := details[0].Callees
+			sort.Slice(got, func(i, j int) bool { return got[i].Function < got[j].Function })
+			for i := range got {
+				got[i].Sites = nil
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("callees: got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package golang
+
+import (
+	"sort"
+
+	"github.com/ryo-arima/ctree/pkg/entity/model"
+)
+
+// CallPathOptions controls the call path search of FindCallPaths
+type CallPathOptions struct {
+	All     bool // every simple path instead of only the shortest one
+	Limit   int  // maximum number of paths when All is set, unlimited when 0
+	MaxHops int  // maximum number of calls in a path when All is set
+}
+
+// FindCallPaths searches the call graph for chains of calls from any of the from functions
+// to any of the to functions. Without opts.All only a shortest path is returned; otherwise
+// simple paths, visiting no function twice, are returned shortest first.
+func (u *goPureProjectGenerateUsecase) FindCallPaths(ctree *model.CTree, from []string, to []string, opts CallPathOptions) []model.CallPath {
+	graph := u.newCallGraph(ctree)
+	targets := make(map[string]bool)
+	for _, key := range to {
+		targets[key] = true
+	}
+
+	if !opts.All {
+		if path, ok := u.findShortestPath(graph, from, targets); ok {
+			return []model.CallPath{path}
+		}
+		return nil
+	}
+
+	// Paths are searched one length at a time, so the limit keeps the shortest ones
+	var paths []model.CallPath
+	for length := 0; length <= opts.MaxHops; length++ {
+		longer := false
+		for _, start := range from {
+			onPath := map[string]bool{start: true}
+			if u.findSimplePaths(graph, start, start, targets, onPath, nil, length, opts.Limit, &paths) {
+				longer = true
+			}
+		}
+		if !longer || (opts.Limit > 0 && len(paths) >= opts.Limit) {
+			break
+		}
+	}
+	return paths
+}
+
+// newCallGraph indexes the outgoing edges of every function, ordered by callee. Calls outside
+// the analyzed source, which have no call graph edge, become edges without a call site.
+func (u *goPureProjectGenerateUsecase) newCallGraph(ctree *model.CTree) map[string][]model.CallEdge {
+	graph := make(map[string][]model.CallEdge)
+	hasEdge := make(map[string]bool) // caller key + " " + callee key
+	for _, edge := range ctree.CallGraph {
+		hasEdge[edge.From+" "+edge.To] = true
+		graph[edge.From] = append(graph[edge.From], edge)
+	}
+	for _, fn := range ctree.Functions {
+		key := u.getFunctionKey(fn)
+		for _, callee := range fn.CallsTo {
+			if !hasEdge[key+" "+callee] {
+				hasEdge[key+" "+callee] = true
+				graph[key] = append(graph[key], model.CallEdge{From: key, To: callee})
+			}
+		}
+	}
+
+	for _, edges := range graph {
+		sort.SliceStable(edges, func(i, j int) bool { return edges[i].To < edges[j].To })
+	}
+	return graph
+}
+
+// findShortestPath runs a breadth-first search from every start function at once
+func (u *goPureProjectGenerateUsecase) findShortestPath(graph map[string][]model.CallEdge, from []string, targets map[string]bool) (model.CallPath, bool) {
+	reachedBy := make(map[string]model.CallEdge) // function key -> edge it was first reached through
+	visited := make(map[string]bool)
+	var queue []string
+	for _, start := range from {
+		if !visited[start] {
+			visited[start] = true
+			queue = append(queue, start)
+		}
+	}
+
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		if targets[key] {
+			var hops []model.CallEdge
+			for current := key; ; {
+				edge, ok := reachedBy[current]
+				if !ok {
+					return model.CallPath{From: current, To: key, Hops: hops}, true
+				}
+				hops = append([]model.CallEdge{edge}, hops...)
+				current = edge.From
+			}
+		}
+		for _, edge := range graph[key] {
+			if !visited[edge.To] {
+				visited[edge.To] = true
+				reachedBy[edge.To] = edge
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+
+	return model.CallPath{}, false
+}
+
+// findSimplePaths collects the paths from key to the targets made of exactly length calls
+// in depth-first order, stopping once limit paths were found when limit is positive. It
+// reports whether a path was cut at length calls, so that longer paths may exist.
+func (u *goPureProjectGenerateUsecase) findSimplePaths(graph map[string][]model.CallEdge, start string, key string, targets map[string]bool, onPath map[string]bool, hops []model.CallEdge, length int, limit int, paths *[]model.CallPath) bool {
+	if targets[key] {
+		if len(hops) == length {
+			*paths = append(*paths, model.CallPath{From: start, To: key, Hops: append([]model.CallEdge(nil), hops...)})
+		}
+		return false
+	}
+	if len(hops) == length {
+		return true
+	}
+
+	cut := false
+	for _, edge := range graph[key] {
+		if limit > 0 && len(*paths) >= limit {
+			break
+		}
+		if onPath[edge.To] {
+			continue
+		}
+		onPath[edge.To] = true
+		if u.findSimplePaths(graph, start, edge.To, targets, onPath, append(hops, edge), length, limit, paths) {
+			cut = true
+		}
+		onPath[edge.To] = false
+	}
+	return cut
+}
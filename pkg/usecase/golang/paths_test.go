@@ -0,0 +1,70 @@
+package golang
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ryo-arima/ctree/pkg/entity/model"
+)
+
+// newPathsCTree returns a call tree where main reaches target through a long chain
+// ordered first and through two short ones
+func newPathsCTree() *model.CTree {
+	ctree := &model.CTree{}
+	calls := [][2]string{
+		{"main", "a"}, {"a", "b"}, {"b", "c"}, {"c", "target"},
+		{"main", "y"}, {"y", "target"},
+		{"main", "z"}, {"z", "target"},
+		{"c", "a"},
+	}
+	for _, name := range []string{"main", "a", "b", "c", "y", "z", "target"} {
+		ctree.Functions = append(ctree.Functions, model.Function{Name: name, PackagePath: "example.com/paths"})
+	}
+	// Calls outside the analyzed source only appear in CallsTo
+	ctree.Functions[3].CallsTo = []string{"os.Exit"}
+	for _, call := range calls {
+		ctree.CallGraph = append(ctree.CallGraph, model.CallEdge{
+			From: "example.com/paths." + call[0],
+			To:   "example.com/paths." + call[1],
+		})
+	}
+	return ctree
+}
+
+func TestFindCallPaths(t *testing.T) {
+	tests := []struct {
+		name string
+		to   string
+		opts CallPathOptions
+		want []string
+	}{
+		{"shortest", "example.com/paths.target", CallPathOptions{}, []string{"main y target"}},
+		{"all", "example.com/paths.target", CallPathOptions{All: true, Limit: 10, MaxHops: 10}, []string{"main y target", "main z target", "main a b c target"}},
+		{"limit keeps the shortest", "example.com/paths.target", CallPathOptions{All: true, Limit: 2, MaxHops: 10}, []string{"main y target", "main z target"}},
+		{"no limit", "example.com/paths.target", CallPathOptions{All: true, Limit: 0, MaxHops: 10}, []string{"main y target", "main z target", "main a b c target"}},
+		{"max hops", "example.com/paths.target", CallPathOptions{All: true, Limit: 10, MaxHops: 3}, []string{"main y target", "main z target"}},
+		{"external callee", "os.Exit", CallPathOptions{}, []string{"main a b c os.Exit"}},
+		{"unreachable", "example.com/paths.missing", CallPathOptions{All: true, Limit: 10, MaxHops: 10}, nil},
+	}
+
+	ctree := newPathsCTree()
+	u := &goPureProjectGenerateUsecase{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths := u.FindCallPaths(ctree, []string{"example.com/paths.main"}, []string{tt.to}, tt.opts)
+
+			var got []string
+			for _, path := range paths {
+				names := []string{strings.TrimPrefix(path.From, "example.com/paths.")}
+				for _, hop := range path.Hops {
+					names = append(names, strings.TrimPrefix(hop.To, "example.com/paths."))
+				}
+				got = append(got, strings.Join(names, " "))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("paths: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
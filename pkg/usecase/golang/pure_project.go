@@ -3,8 +3,15 @@ package golang
 import (
 	"fmt"
 	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/ryo-arima/ctree/pkg/config"
 	"github.com/ryo-arima/ctree/pkg/entity/model"
@@ -16,6 +23,15 @@ import (
 // GoPureProjectGenerateUsecase handles pure Go project specific generation
 type GoPureProjectGenerateUsecase interface {
 	Generate(req request.GenerateRequest, format string) (string, error)
+	Analyze(req request.GenerateRequest) (*model.CTree, error)
+	SummarizeFunctions(ctree *model.CTree) []model.FunctionSummary
+	DescribeFunctions(ctree *model.CTree, match func(summary model.FunctionSummary) bool) []model.FunctionDetail
+	BuildCallerTree(ctree *model.CTree, targets []string) []model.CallTreeNode
+	FindCallPaths(ctree *model.CTree, from []string, to []string, opts CallPathOptions) []model.CallPath
+	ListClasses(req request.GenerateRequest) ([]model.Class, error)
+	ListImplementations(req request.GenerateRequest) ([]model.Implementation, error)
+	ListVariables(req request.GenerateRequest) ([]model.Variable, error)
+	ListImports(req request.GenerateRequest) (*model.ImportReport, error)
 }
 
 type goPureProjectGenerateUsecase struct {
@@ -23,6 +39,30 @@ type goPureProjectGenerateUsecase struct {
 	repo   golang.GoPureProjectRepository
 }
 
+// callTarget represents a function called from another function
+type callTarget struct {
+	Key         string // callee key as produced by getFunctionKey, or the name as written when unresolved
+	Package     string // callee package name when resolved outside the calling package
+	PackagePath string // callee import path when resolved outside the calling package
+	Resolved    bool   // true when the type checker identified the callee
+	Dynamic     bool   // true when the call dispatches through an interface method
+	Field       bool   // true when the call goes through a function-typed struct field
+	Kind        string // how the callee is invoked: call, go, defer or ref
+	Func        *types.Func
+	Sites       []model.CallSite
+	Builtin     bool   // true for predeclared functions such as len and append
+	Conversion  bool   // true for type conversions such as time.Duration(n)
+	Via         string // embedded fields a promoted method or field is reached through, e.g. Base.Logger
+}
+
+// Call kinds recorded on call edges
+const (
+	callKindCall  = "call"
+	callKindGo    = "go"
+	callKindDefer = "defer"
+	callKindRef   = "ref" // function or method value taken without calling it
+)
+
 // NewGoPureProjectGenerateUsecase creates new Go pure project analyze usecase
 func NewGoPureProjectGenerateUsecase(conf *config.Config) GoPureProjectGenerateUsecase {
 	return &goPureProjectGenerateUsecase{
@@ -33,88 +73,152 @@ func NewGoPureProjectGenerateUsecase(conf *config.Config) GoPureProjectGenerateU
 
 // Generate performs Go pure project specific source code generation
 func (u *goPureProjectGenerateUsecase) Generate(req request.GenerateRequest, format string) (string, error) {
-	// Find all Go files
-	goFiles, err := u.repo.FindGoFiles(req.SourcePath, req.Recursive, req.MaxDepth)
+	ctree, err := u.Analyze(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to find Go files: %w", err)
+		return "", err
 	}
 
-	if len(goFiles) == 0 {
-		return "", fmt.Errorf("no Go files found in %s", req.SourcePath)
+	// Log entry points found
+	if len(ctree.EntryPoints) > 0 {
+		fmt.Fprintf(os.Stderr, "Found %d entry point(s):\n", len(ctree.EntryPoints))
+		for _, ep := range ctree.EntryPoints {
+			fmt.Fprintf(os.Stderr, "  - %s in %s:%d\n", ep.Name, ep.File, ep.Line)
+		}
+	} else {
+		fmt.Fprintf(os.Stderr, "Warning: No entry points (main, init or test functions) found; use --entry or --exported-as-entry for libraries\n")
+	}
+
+	// Format output
+	switch strings.ToLower(format) {
+	case "yaml", "yml", "":
+		data, err := yaml.Marshal(ctree)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal to YAML: %w", err)
+		}
+		return string(data), nil
+	default:
+		return "", fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// Analyze parses and type-checks the source and builds its functions, call graph and call tree
+func (u *goPureProjectGenerateUsecase) Analyze(req request.GenerateRequest) (*model.CTree, error) {
+	switch req.Dispatch {
+	case "", "static", "cha":
+	default:
+		return nil, fmt.Errorf("unsupported dispatch mode: %s (supported: static, cha)", req.Dispatch)
+	}
+	for _, pattern := range req.EntryPatterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid entry pattern %q: %w", pattern, err)
+		}
+	}
+
+	if req.FollowDeps < 0 {
+		return nil, fmt.Errorf("invalid follow-deps level: %d", req.FollowDeps)
+	}
+
+	packages, err := u.loadPackages(req)
+	if err != nil {
+		return nil, err
 	}
 
-	// Parse all files and extract functions
+	// Extract functions from every file
 	var allFunctions []model.Function
 	var entryPoints []model.Function
-	functionCalls := make(map[string][]string) // function name -> called functions
-	importMap := make(map[string]string)       // package name -> full import path
+	functionCalls := make(map[string][]callTarget)    // function key -> called functions
+	fileImports := make(map[string]map[string]string) // file -> package name -> full import path
+	closures := &closureCollector{
+		vars:     make(map[types.Object]string),
+		counters: make(map[string]int),
+		calls:    functionCalls,
+	}
 
-	for _, filePath := range goFiles {
-		file, fset, err := u.repo.ParseGoFile(filePath)
-		if err != nil {
-			// Log error but continue with other files
-			fmt.Printf("Warning: failed to parse %s: %v\n", filePath, err)
-			continue
+	// Closures assigned to package-level variables are collected first so that
+	// calls through those variables resolve regardless of file order
+	for _, pkg := range packages {
+		for i, file := range pkg.Files {
+			relPath := u.getRelativePath(pkg.FilePaths[i])
+			u.collectGlobalClosures(closures, file, pkg, relPath, u.getFileConstraint(req, file, relPath))
 		}
+	}
 
-		// Extract import information
-		fileImports := u.repo.ExtractImports(file)
-		for pkg, path := range fileImports {
-			importMap[pkg] = path
+	for _, pkg := range packages {
+		if len(pkg.TypeErrors) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: type checking of %s reported %d error(s); unresolved calls fall back to name matching\n", u.getRelativePath(pkg.Dir), len(pkg.TypeErrors))
 		}
 
-		// Convert to relative path before extracting functions
-		relPath := u.getRelativePath(filePath)
-		functions, err := u.repo.ExtractFunctions(file, fset, relPath)
-		if err != nil {
-			fmt.Printf("Warning: failed to extract functions from %s: %v\n", relPath, err)
-			continue
-		}
+		functions, pkgEntryPoints := u.analyzePackage(req, pkg, closures, fileImports, u.getRelativePath)
+		allFunctions = append(allFunctions, functions...)
+		entryPoints = append(entryPoints, pkgEntryPoints...)
+	}
+	allFunctions = append(allFunctions, closures.functions...)
 
-		// Find entry points (main functions and init functions)
-		for _, fn := range functions {
-			if fn.Name == "main" && fn.Package == "main" {
-				fn.Kind = "entrypoint"
+	// Entry points requested with --entry patterns or library mode
+	if len(req.EntryPatterns) > 0 || req.ExportedAsEntry {
+		isEntry := make(map[string]bool)
+		for _, ep := range entryPoints {
+			isEntry[u.getFunctionKey(ep)] = true
+		}
+		for _, fn := range allFunctions {
+			if isEntry[u.getFunctionKey(fn)] {
+				continue
+			}
+			if u.matchEntryPattern(fn, req.EntryPatterns) {
+				fn.Kind = "entry"
 				entryPoints = append(entryPoints, fn)
-			} else if fn.Name == "init" {
-				fn.Kind = "initializer"
+			} else if req.ExportedAsEntry && u.isExportedFunction(fn) {
+				fn.Kind = "exported"
 				entryPoints = append(entryPoints, fn)
 			}
-
-			// Extract function calls
-			calls := u.extractFunctionCalls(file, fn.Name)
-			functionKey := u.getFunctionKey(fn)
-			functionCalls[functionKey] = calls
 		}
+	}
 
-		allFunctions = append(allFunctions, functions...)
+	// Module information tells dependencies apart and locates their source
+	modules, err := u.repo.LoadModuleInfo(req.SourcePath)
+	if err != nil {
+		// Without module information, only the standard library can be told apart
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	u.assignModules(allFunctions, modules)
+	u.assignModules(entryPoints, modules)
+	if req.FollowDeps > 0 {
+		allFunctions = append(allFunctions, u.followDependencies(req, allFunctions, modules, closures, fileImports)...)
 	}
 
-	// Log entry points found
-	if len(entryPoints) > 0 {
-		fmt.Printf("Found %d entry point(s):\n", len(entryPoints))
-		for _, ep := range entryPoints {
-			fmt.Printf("  - %s in %s:%d\n", ep.Name, ep.File, ep.Line)
+	if req.Dispatch == "cha" {
+		for funcKey, calls := range functionCalls {
+			functionCalls[funcKey] = u.expandDynamicCalls(calls, packages)
 		}
-	} else {
-		fmt.Printf("Warning: No entry points (main or init functions) found\n")
 	}
 
+	// Create function map for quick lookup
+	funcIndex := u.newFunctionIndex(allFunctions)
+
 	// Build call graph
 	var callGraph []model.CallEdge
 	for funcKey, calls := range functionCalls {
-		for _, calledFunc := range calls {
+		for _, call := range calls {
 			// Find the function details
-			for _, fn := range allFunctions {
-				if fn.Name == calledFunc || u.getFunctionKey(fn) == calledFunc {
-					callGraph = append(callGraph, model.CallEdge{
-						From: funcKey,
-						To:   u.getFunctionKey(fn),
-						File: fn.File,
-						Line: fn.Line,
-					})
-					break
+			for _, fn := range u.lookupFunctions(funcIndex, call) {
+				edge := model.CallEdge{
+					From:     funcKey,
+					To:       u.getFunctionKey(fn),
+					Count:    len(call.Sites),
+					Dynamic:  call.Dynamic,
+					Via:      call.Via,
+					CallKind: call.Kind,
+				}
+				// The edge location is the first call site; all of them are listed when there are several
+				if len(call.Sites) > 0 {
+					edge.File = call.Sites[0].File
+					edge.Line = call.Sites[0].Line
+					edge.Column = call.Sites[0].Column
 				}
+				if len(call.Sites) > 1 {
+					edge.Sites = call.Sites
+				}
+				callGraph = append(callGraph, edge)
 			}
 		}
 	}
@@ -122,17 +226,33 @@ func (u *goPureProjectGenerateUsecase) Generate(req request.GenerateRequest, for
 	// Update functions with CallsTo information
 	for i := range allFunctions {
 		funcKey := u.getFunctionKey(allFunctions[i])
-		if calls, ok := functionCalls[funcKey]; ok {
-			allFunctions[i].CallsTo = calls
+		for _, call := range functionCalls[funcKey] {
+			allFunctions[i].CallsTo = append(allFunctions[i].CallsTo, call.Key)
+			if call.Dynamic {
+				allFunctions[i].DynamicCalls = append(allFunctions[i].DynamicCalls, call.Key)
+			}
+			if call.Field {
+				allFunctions[i].FieldCalls = append(allFunctions[i].FieldCalls, call.Key)
+			}
 		}
 	}
 
 	// Build hierarchical call tree from entry points
-	callTreeNodes := u.buildHierarchicalCallTree(entryPoints, allFunctions, functionCalls, importMap)
+	source := &callTreeSource{
+		index:       funcIndex,
+		calls:       functionCalls,
+		fileImports: fileImports,
+		modules:     modules,
+		followDeps:  req.FollowDeps,
+	}
+	callTreeNodes := u.buildHierarchicalCallTree(entryPoints, source)
 
 	// Build call tree visualization text
 	callTreeData := u.buildCallTreeVisualization(callTreeNodes)
 
+	moduleSummaries := u.buildModuleSummaries(modules, allFunctions, entryPoints)
+	implementations := u.findInterfaceImplementations(packages)
+
 	// Create call tree
 	ctree := model.CTree{
 		SourceFile:            u.getRelativePath(req.SourcePath),
@@ -142,46 +262,479 @@ func (u *goPureProjectGenerateUsecase) Generate(req request.GenerateRequest, for
 		EntryPoints:           entryPoints,
 		CallTree:              callTreeNodes,
 		CallTreeVisualization: callTreeData,
+		FileImports:           fileImports,
+		Modules:               moduleSummaries,
+		Implements:            implementations,
 		Metadata: map[string]interface{}{
 			"total_functions": len(allFunctions),
 			"entry_points":    len(entryPoints),
 			"call_edges":      len(callGraph),
+			"modules":         len(moduleSummaries),
+			"implementations": len(implementations),
 		},
 	}
 
-	// Format output
-	switch strings.ToLower(format) {
-	case "yaml", "yml", "":
-		data, err := yaml.Marshal(ctree)
+	return &ctree, nil
+}
+
+// loadPackages finds the Go files selected by the request, then parses and type-checks them
+func (u *goPureProjectGenerateUsecase) loadPackages(req request.GenerateRequest) ([]*golang.GoPackage, error) {
+	goFiles, err := u.repo.FindGoFiles(req.SourcePath, req.Recursive, req.MaxDepth, u.getBuildOptions(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find Go files: %w", err)
+	}
+
+	if len(goFiles) == 0 {
+		return nil, fmt.Errorf("no Go files found in %s", req.SourcePath)
+	}
+
+	packages, parseErrors := u.repo.LoadPackages(req.SourcePath, goFiles, u.getBuildOptions(req))
+	for _, err := range parseErrors {
+		// Log error but continue with other files
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return packages, nil
+}
+
+// assignModules records the main module that provides each function
+func (u *goPureProjectGenerateUsecase) assignModules(functions []model.Function, modules *golang.ModuleInfo) {
+	if modules == nil {
+		return
+	}
+	for i := range functions {
+		if mainModule := modules.MainModule(functions[i].PackagePath); mainModule != nil {
+			functions[i].Module = mainModule.Path
+		}
+	}
+}
+
+// buildModuleSummaries counts the packages, functions and entry points of every main module
+func (u *goPureProjectGenerateUsecase) buildModuleSummaries(modules *golang.ModuleInfo, functions []model.Function, entryPoints []model.Function) []model.ModuleSummary {
+	if modules == nil {
+		return nil
+	}
+
+	summaries := make([]model.ModuleSummary, len(modules.MainModules))
+	index := make(map[string]int) // module path -> summary
+	for i, module := range modules.MainModules {
+		summaries[i] = model.ModuleSummary{
+			Path: module.Path,
+			Dir:  u.getRelativePath(module.Dir),
+		}
+		index[module.Path] = i
+	}
+
+	packages := make(map[string]bool)
+	for _, fn := range functions {
+		i, ok := index[fn.Module]
+		if !ok {
+			continue
+		}
+		summaries[i].Functions++
+		if !packages[fn.PackagePath] {
+			packages[fn.PackagePath] = true
+			summaries[i].Packages++
+		}
+	}
+	for _, ep := range entryPoints {
+		if i, ok := index[ep.Module]; ok {
+			summaries[i].EntryPoints++
+		}
+	}
+
+	return summaries
+}
+
+// getBuildOptions returns the file selection options requested for the analyzed source
+func (u *goPureProjectGenerateUsecase) getBuildOptions(req request.GenerateRequest) golang.BuildOptions {
+	return golang.BuildOptions{
+		GOOS:         req.GOOS,
+		GOARCH:       req.GOARCH,
+		Tags:         req.Tags,
+		AllPlatforms: req.AllPlatforms,
+		IncludeTests: req.IncludeTests,
+	}
+}
+
+// followDependencies analyzes the source of third-party packages called from functions,
+// one package hop per level up to req.FollowDeps, and returns the dependency functions found.
+// Source is taken from vendor/ or the module cache; missing packages are reported and skipped.
+func (u *goPureProjectGenerateUsecase) followDependencies(req request.GenerateRequest, functions []model.Function, modules *golang.ModuleInfo, closures *closureCollector, fileImports map[string]map[string]string) []model.Function {
+	// Tests of dependencies are never part of the call tree
+	buildOptions := u.getBuildOptions(req)
+	buildOptions.IncludeTests = false
+
+	var dependencyFunctions []model.Function
+	visited := make(map[string]bool) // package path -> already considered
+	callers := functions
+	for level := 0; level < req.FollowDeps && len(callers) > 0; level++ {
+		// Third-party packages called from the previous level
+		dependencies := make(map[string]*golang.DependencyPackage) // package dir or path -> package
+		var goFiles []string
+		for _, fn := range callers {
+			for _, call := range closures.calls[u.getFunctionKey(fn)] {
+				if call.PackagePath == "" || visited[call.PackagePath] {
+					continue
+				}
+				visited[call.PackagePath] = true
+				if origin, _, _ := u.classifyPackagePath(call.PackagePath, modules); origin != originThirdParty {
+					continue
+				}
+
+				dep, err := u.repo.FindDependencyPackage(call.PackagePath, modules)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+					continue
+				}
+				files, err := u.repo.FindGoFiles(dep.Dir, false, 0, buildOptions)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to find Go files of %s: %v\n", dep.Path, err)
+					continue
+				}
+				dependencies[dep.Dir] = dep
+				dependencies[dep.Path] = dep
+				goFiles = append(goFiles, files...)
+			}
+		}
+		if len(goFiles) == 0 {
+			break
+		}
+
+		// Dependency packages are found through their module, so no source root applies
+		packages, parseErrors := u.repo.LoadPackages("", goFiles, buildOptions)
+		for _, err := range parseErrors {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+
+		// Dependency files are recorded as module@version/file so that they read like go tool output
+		relPath := func(filePath string) string {
+			dep, ok := dependencies[filepath.Dir(filePath)]
+			if !ok {
+				return u.getRelativePath(filePath)
+			}
+			rel, err := filepath.Rel(dep.ModuleDir, filePath)
+			if err != nil {
+				return u.getRelativePath(filePath)
+			}
+			return fmt.Sprintf("%s@%s/%s", dep.Module, dep.Version, filepath.ToSlash(rel))
+		}
+
+		closureCount := len(closures.functions)
+		for _, pkg := range packages {
+			for i, file := range pkg.Files {
+				filePath := relPath(pkg.FilePaths[i])
+				u.collectGlobalClosures(closures, file, pkg, filePath, u.getFileConstraint(req, file, filePath))
+			}
+		}
+		callers = nil
+		for _, pkg := range packages {
+			// Entry points of dependencies, such as their init functions, are not followed on their own
+			functions, _ := u.analyzePackage(req, pkg, closures, fileImports, relPath)
+			callers = append(callers, functions...)
+		}
+		callers = append(callers, closures.functions[closureCount:]...)
+
+		for i := range callers {
+			if dep, ok := dependencies[callers[i].PackagePath]; ok {
+				callers[i].Module = dep.Module
+				callers[i].ModuleVersion = dep.Version
+			}
+		}
+		dependencyFunctions = append(dependencyFunctions, callers...)
+	}
+
+	if len(dependencyFunctions) > 0 {
+		fmt.Fprintf(os.Stderr, "Followed %d function(s) in dependency source\n", len(dependencyFunctions))
+	}
+	return dependencyFunctions
+}
+
+// analyzePackage extracts the functions of a package and the calls they make.
+// relPath converts file paths to the form recorded on functions and call sites.
+func (u *goPureProjectGenerateUsecase) analyzePackage(req request.GenerateRequest, pkg *golang.GoPackage, closures *closureCollector, fileImports map[string]map[string]string, relPath func(string) string) ([]model.Function, []model.Function) {
+	var packageFunctions []model.Function
+	var entryPoints []model.Function
+
+	for i, file := range pkg.Files {
+		// Convert to relative path before extracting functions
+		filePath := relPath(pkg.FilePaths[i])
+
+		// Extract import information; aliases are only valid within their file
+		fileImports[filePath] = u.repo.ExtractImports(file)
+		functions, err := u.repo.ExtractFunctions(file, pkg.Fset, filePath)
 		if err != nil {
-			return "", fmt.Errorf("failed to marshal to YAML: %w", err)
+			fmt.Fprintf(os.Stderr, "Warning: failed to extract functions from %s: %v\n", filePath, err)
+			continue
 		}
-		return string(data), nil
-	default:
-		return "", fmt.Errorf("unsupported format: %s", format)
+		constraint := u.getFileConstraint(req, file, filePath)
+		for j := range functions {
+			functions[j].Constraint = constraint
+			functions[j].PackagePath = pkg.Path
+		}
+
+		funcDecls := u.indexFuncDecls(file, pkg)
+		for _, fn := range functions {
+			decl := funcDecls[fn.Line]
+
+			// Find entry points (main, init and test functions, or marked with //ctree:entry)
+			if kind := u.getEntryPointKind(fn, u.getDeclSignature(decl, pkg)); kind != "" {
+				fn.Kind = kind
+				entryPoints = append(entryPoints, fn)
+			} else if u.hasEntryDirective(decl) {
+				fn.Kind = "entry"
+				entryPoints = append(entryPoints, fn)
+			}
+
+			// Extract function calls
+			functionKey := u.getFunctionKey(fn)
+			if decl != nil && decl.Body != nil {
+				closures.calls[functionKey] = u.extractFunctionCalls(closures, fn, decl.Body, pkg)
+			}
+		}
+
+		packageFunctions = append(packageFunctions, functions...)
 	}
+
+	return packageFunctions, entryPoints
 }
 
-// extractFunctionCalls extracts function calls from a function body
-func (u *goPureProjectGenerateUsecase) extractFunctionCalls(file *ast.File, funcName string) []string {
-	var calls []string
-	callMap := make(map[string]bool)
+// getEntryPointKind returns the entry point kind of a function, or "" if it is not an entry point.
+// Test entry points follow the naming and signature rules of go test and are only found in
+// _test.go files. sig is nil when the declaration was not type-checked.
+func (u *goPureProjectGenerateUsecase) getEntryPointKind(fn model.Function, sig *types.Signature) string {
+	if fn.Receiver != "" {
+		return ""
+	}
+	if fn.Name == "main" && fn.Package == "main" {
+		return "entrypoint"
+	}
+	if fn.Name == "init" {
+		return "initializer"
+	}
+	if !strings.HasSuffix(fn.File, "_test.go") {
+		return ""
+	}
+
+	switch {
+	case fn.Name == "TestMain" && u.hasTestSignature(fn, sig, "M"):
+		return "testmain"
+	case isTestName(fn.Name, "Test") && u.hasTestSignature(fn, sig, "T"):
+		return "test"
+	case isTestName(fn.Name, "Benchmark") && u.hasTestSignature(fn, sig, "B"):
+		return "benchmark"
+	case isTestName(fn.Name, "Fuzz") && u.hasTestSignature(fn, sig, "F"):
+		return "fuzz"
+	case isTestName(fn.Name, "Example") && u.hasTestSignature(fn, sig, ""):
+		return "example"
+	}
+	return ""
+}
 
-	// Find the function
-	ast.Inspect(file, func(n ast.Node) bool {
-		if fn, ok := n.(*ast.FuncDecl); ok && fn.Name.Name == funcName {
-			// Inspect function body
-			ast.Inspect(fn.Body, func(node ast.Node) bool {
-				if callExpr, ok := node.(*ast.CallExpr); ok {
-					callName := u.getCallName(callExpr.Fun)
-					if callName != "" && !callMap[callName] {
-						callMap[callName] = true
-						calls = append(calls, callName)
+// hasTestSignature reports whether a function takes a single *testing.<param>, or nothing when
+// param is empty, and returns nothing, as go test requires. Without type information the
+// parameter type is compared as written.
+func (u *goPureProjectGenerateUsecase) hasTestSignature(fn model.Function, sig *types.Signature, param string) bool {
+	if sig == nil {
+		if len(fn.ReturnTypes) > 0 || len(fn.TypeParams) > 0 {
+			return false
+		}
+		if param == "" {
+			return len(fn.Parameters) == 0
+		}
+		return len(fn.Parameters) == 1 && fn.Parameters[0].Type == "*testing."+param
+	}
+
+	if sig.Results().Len() > 0 || sig.TypeParams().Len() > 0 {
+		return false
+	}
+	if param == "" {
+		return sig.Params().Len() == 0
+	}
+	if sig.Params().Len() != 1 {
+		return false
+	}
+	ptr, ok := types.Unalias(sig.Params().At(0).Type()).(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := types.Unalias(ptr.Elem()).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "testing" && named.Obj().Name() == param
+}
+
+// getDeclSignature returns the type-checked signature of a function declaration, or nil
+func (u *goPureProjectGenerateUsecase) getDeclSignature(decl *ast.FuncDecl, pkg *golang.GoPackage) *types.Signature {
+	if decl == nil || pkg.Info == nil {
+		return nil
+	}
+	if fn, ok := pkg.Info.Defs[decl.Name].(*types.Func); ok {
+		return fn.Signature()
+	}
+	return nil
+}
+
+// hasEntryDirective reports whether a function declaration is marked with a //ctree:entry comment
+func (u *goPureProjectGenerateUsecase) hasEntryDirective(decl *ast.FuncDecl) bool {
+	if decl == nil || decl.Doc == nil {
+		return false
+	}
+	for _, comment := range decl.Doc.List {
+		if strings.TrimSpace(comment.Text) == "//ctree:entry" {
+			return true
+		}
+	}
+	return false
+}
+
+// matchEntryPattern reports whether a function matches one of the --entry patterns.
+// A pattern is matched against the function qualified by its import path, by its directory
+// relative to the working directory, and by its package name, e.g. pkg/usecase/golang.*.Generate
+func (u *goPureProjectGenerateUsecase) matchEntryPattern(fn model.Function, patterns []string) bool {
+	var suffix string
+	if fn.Receiver != "" {
+		suffix = "." + fn.Receiver + "." + fn.Name
+	} else {
+		suffix = "." + fn.Name
+	}
+	candidates := []string{
+		fn.PackagePath + suffix,
+		filepath.ToSlash(filepath.Dir(fn.File)) + suffix,
+		fn.Package + suffix,
+	}
+
+	for _, pattern := range patterns {
+		for _, candidate := range candidates {
+			if matched, _ := path.Match(pattern, candidate); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isExportedFunction reports whether a function or method is part of the package API
+func (u *goPureProjectGenerateUsecase) isExportedFunction(fn model.Function) bool {
+	if fn.Kind == "closure" || !ast.IsExported(fn.Name) {
+		return false
+	}
+	return fn.Receiver == "" || ast.IsExported(fn.Receiver)
+}
+
+// isTestName reports whether name is prefix followed by nothing or by a non-lowercase rune,
+// e.g. TestFoo or Test but not Testing
+func isTestName(name, prefix string) bool {
+	if !strings.HasPrefix(name, prefix) {
+		return false
+	}
+	if len(name) == len(prefix) {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(name[len(prefix):])
+	return !unicode.IsLower(r)
+}
+
+// indexFuncDecls maps the line of each function declaration in a file to its AST node
+func (u *goPureProjectGenerateUsecase) indexFuncDecls(file *ast.File, pkg *golang.GoPackage) map[int]*ast.FuncDecl {
+	funcDecls := make(map[int]*ast.FuncDecl)
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			funcDecls[pkg.Fset.Position(fn.Pos()).Line] = fn
+		}
+	}
+	return funcDecls
+}
+
+// extractFunctionCalls extracts function calls from a function body.
+// Function literals are not attributed to the enclosing function; each becomes a closure
+// with its own calls, and the enclosing function gets an edge to the closure instead.
+func (u *goPureProjectGenerateUsecase) extractFunctionCalls(c *closureCollector, owner model.Function, body ast.Node, pkg *golang.GoPackage) []callTarget {
+	var calls []callTarget
+	callMap := make(map[string]int) // call kind + key -> index in calls
+	addCall := func(call callTarget, pos token.Pos) {
+		if call.Key == "" {
+			return
+		}
+		position := pkg.Fset.Position(pos)
+		site := model.CallSite{File: owner.File, Line: position.Line, Column: position.Column}
+
+		// Repeated calls to the same callee are merged, keeping every call site
+		if i, ok := callMap[call.Kind+":"+call.Key]; ok {
+			calls[i].Sites = append(calls[i].Sites, site)
+			return
+		}
+		call.Sites = []model.CallSite{site}
+		callMap[call.Kind+":"+call.Key] = len(calls)
+		calls = append(calls, call)
+	}
+
+	holders := make(map[*ast.FuncLit]types.Object) // function literal -> variable it is assigned to
+	callKinds := make(map[ast.Node]string)         // call expression or invoked literal -> go/defer
+	callees := make(map[ast.Expr]bool)             // expressions in call position
+	selected := make(map[*ast.Ident]bool)          // identifiers already handled as a selector
+
+	// Inspect function body
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.GoStmt:
+			callKinds[n.Call] = callKindGo
+		case *ast.DeferStmt:
+			callKinds[n.Call] = callKindDefer
+		case *ast.AssignStmt:
+			if len(n.Lhs) == len(n.Rhs) {
+				for i, rhs := range n.Rhs {
+					lit, isLit := rhs.(*ast.FuncLit)
+					ident, isIdent := n.Lhs[i].(*ast.Ident)
+					if isLit && isIdent {
+						holders[lit] = u.getIdentObject(ident, pkg)
 					}
 				}
-				return true
-			})
+			}
+		case *ast.ValueSpec:
+			for i, value := range n.Values {
+				if lit, ok := value.(*ast.FuncLit); ok && i < len(n.Names) {
+					holders[lit] = u.getIdentObject(n.Names[i], pkg)
+				}
+			}
+		case *ast.FuncLit:
+			// A literal that is not invoked in place is only referenced
+			kind, invoked := callKinds[n]
+			if !invoked {
+				kind = callKindRef
+			}
+			closure := u.extractClosure(c, owner, n, pkg, holders[n])
+			addCall(callTarget{Key: u.getFunctionKey(closure), Resolved: true, Kind: kind}, n.Pos())
 			return false
+		case *ast.CallExpr:
+			kind, ok := callKinds[n]
+			if !ok {
+				kind = callKindCall
+			}
+			fun := u.unwrapInstantiation(n.Fun, pkg)
+			if lit, ok := fun.(*ast.FuncLit); ok {
+				callKinds[lit] = kind
+				return true
+			}
+			callees[fun] = true
+			call := u.resolveCall(c, fun, pkg)
+			if pkg.Info != nil {
+				if tv, ok := pkg.Info.Types[n.Fun]; ok && tv.IsType() {
+					call = u.resolveConversion(tv.Type)
+				}
+			}
+			call.Kind = kind
+			addCall(call, n.Lparen)
+		case *ast.SelectorExpr:
+			selected[n.Sel] = true
+			if !callees[n] {
+				if call, ok := u.resolveFuncValue(n, n.Sel, pkg); ok {
+					addCall(call, n.Sel.Pos())
+				}
+			}
+		case *ast.Ident:
+			if !callees[n] && !selected[n] {
+				if call, ok := u.resolveFuncValue(n, n, pkg); ok {
+					addCall(call, n.Pos())
+				}
+			}
 		}
 		return true
 	})
@@ -189,27 +742,533 @@ func (u *goPureProjectGenerateUsecase) extractFunctionCalls(file *ast.File, func
 	return calls
 }
 
-// getCallName extracts the function name from a call expression
-func (u *goPureProjectGenerateUsecase) getCallName(expr ast.Expr) string {
+// unwrapInstantiation strips parentheses and explicit generic instantiations such as
+// Map[string, int] from a callee expression. Index expressions that select an element
+// of a slice or map, as in handlers[i], are kept.
+func (u *goPureProjectGenerateUsecase) unwrapInstantiation(expr ast.Expr, pkg *golang.GoPackage) ast.Expr {
+	for {
+		switch e := expr.(type) {
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			if !u.isInstantiation(e.X, pkg) {
+				return expr
+			}
+			expr = e.X
+		case *ast.IndexListExpr:
+			// Several indices are only valid as type arguments
+			expr = e.X
+		default:
+			return expr
+		}
+	}
+}
+
+// isInstantiation reports whether an indexed expression is a generic function or type,
+// so that indexing it instantiates it with type arguments
+func (u *goPureProjectGenerateUsecase) isInstantiation(expr ast.Expr, pkg *golang.GoPackage) bool {
+	if pkg.Info == nil {
+		return false
+	}
+
+	var ident *ast.Ident
+	switch e := expr.(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	}
+	if ident != nil {
+		if _, ok := pkg.Info.Instances[ident]; ok {
+			return true
+		}
+	}
+	if tv, ok := pkg.Info.Types[expr]; ok {
+		if sig, ok := tv.Type.(*types.Signature); ok && sig.TypeParams().Len() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveFuncValue resolves a function or method used as a value instead of being called,
+// such as a method value passed as a callback
+func (u *goPureProjectGenerateUsecase) resolveFuncValue(expr ast.Expr, ident *ast.Ident, pkg *golang.GoPackage) (callTarget, bool) {
+	fn, ok := pkg.Info.Uses[ident].(*types.Func)
+	if !ok {
+		return callTarget{}, false
+	}
+	call := u.resolveCall(nil, expr, pkg)
+	call.Kind = callKindRef
+	call.Func = fn
+	return call, true
+}
+
+// closureCollector gathers function literals as closures named after their enclosing
+// function the way the Go runtime names them (F.func1, F.func1.1, glob..func1)
+type closureCollector struct {
+	vars      map[types.Object]string // variable holding a function literal -> closure key
+	counters  map[string]int          // enclosing function key -> closures numbered so far
+	functions []model.Function
+	calls     map[string][]callTarget
+}
+
+// lookupVar returns the closure held by a variable; a nil collector holds no closures
+func (c *closureCollector) lookupVar(obj types.Object) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	closureKey, ok := c.vars[obj]
+	return closureKey, ok
+}
+
+// extractClosure registers a function literal as a closure of owner and extracts its calls
+func (u *goPureProjectGenerateUsecase) extractClosure(c *closureCollector, owner model.Function, lit *ast.FuncLit, pkg *golang.GoPackage, holder types.Object) model.Function {
+	ownerKey := u.getFunctionKey(owner)
+	c.counters[ownerKey]++
+
+	name := fmt.Sprintf("%s.func%d", owner.Name, c.counters[ownerKey])
+	if owner.Kind == "closure" {
+		name = fmt.Sprintf("%s.%d", owner.Name, c.counters[ownerKey])
+	}
+
+	closure := model.Function{
+		Name:        name,
+		File:        owner.File,
+		Line:        pkg.Fset.Position(lit.Pos()).Line,
+		EndLine:     pkg.Fset.Position(lit.End()).Line,
+		Kind:        "closure",
+		Package:     owner.Package,
+		PackagePath: owner.PackagePath,
+		Receiver:    owner.Receiver,
+		Constraint:  owner.Constraint,
+	}
+	closure.Parameters, closure.ReturnTypes = u.repo.ExtractSignature(lit.Type)
+
+	closureKey := u.getFunctionKey(closure)
+	if holder != nil {
+		c.vars[holder] = closureKey
+	}
+	c.functions = append(c.functions, closure)
+	c.calls[closureKey] = u.extractFunctionCalls(c, closure, lit.Body, pkg)
+
+	return closure
+}
+
+// collectGlobalClosures extracts the function literals in package-level variable initializers,
+// including those nested in composite literals and call arguments such as the Run function
+// of a cobra.Command
+func (u *goPureProjectGenerateUsecase) collectGlobalClosures(c *closureCollector, file *ast.File, pkg *golang.GoPackage, filePath string, constraint string) {
+	owner := model.Function{
+		Name:        "glob.",
+		File:        filePath,
+		Package:     pkg.Name,
+		PackagePath: pkg.Path,
+		Constraint:  constraint,
+	}
+
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec := spec.(*ast.ValueSpec)
+			for i, value := range valueSpec.Values {
+				ast.Inspect(value, func(node ast.Node) bool {
+					lit, ok := node.(*ast.FuncLit)
+					if !ok {
+						return true
+					}
+					// Only a literal that is the whole value is held by the variable
+					var holder types.Object
+					if ast.Expr(lit) == value && i < len(valueSpec.Names) {
+						holder = u.getIdentObject(valueSpec.Names[i], pkg)
+					}
+					// Literals nested in this one are closures of the closure
+					u.extractClosure(c, owner, lit, pkg, holder)
+					return false
+				})
+			}
+		}
+	}
+}
+
+// getIdentObject returns the object an identifier defines or refers to
+func (u *goPureProjectGenerateUsecase) getIdentObject(ident *ast.Ident, pkg *golang.GoPackage) types.Object {
+	if obj := pkg.Info.Defs[ident]; obj != nil {
+		return obj
+	}
+	return pkg.Info.Uses[ident]
+}
+
+// resolveCall resolves the callee of a call expression using type information,
+// falling back to the name as written in source when the callee is unknown to the type checker
+func (u *goPureProjectGenerateUsecase) resolveCall(c *closureCollector, expr ast.Expr, pkg *golang.GoPackage) callTarget {
+	call := callTarget{Key: u.getCallName(expr, pkg)}
+
+	var ident *ast.Ident
+	switch e := expr.(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	case *ast.ParenExpr, *ast.IndexExpr, *ast.IndexListExpr:
+		if inner := u.unwrapInstantiation(e, pkg); inner != e {
+			return u.resolveCall(c, inner, pkg)
+		}
+		// Call through a function value taken from a slice or map; its target is not known
+		return callTarget{}
+	}
+	if ident == nil || pkg.Info == nil {
+		return call
+	}
+
+	obj, ok := pkg.Info.Uses[ident]
+	if !ok || obj == nil {
+		// Predeclared names are recognized even when type checking failed
+		if e, ok := expr.(*ast.Ident); ok {
+			switch types.Universe.Lookup(e.Name).(type) {
+			case *types.Builtin:
+				call.Builtin = true
+			case *types.TypeName:
+				call.Conversion = true
+			}
+		}
+		return call
+	}
+
+	// The type checker knows the callee, so name-based guessing must not be applied
+	call.Resolved = true
+	if sel, ok := expr.(*ast.SelectorExpr); ok {
+		call.Via = golang.EmbeddingPath(pkg.Info.Selections[sel])
+	}
+	if _, ok := obj.(*types.Builtin); ok {
+		call.Builtin = true
+	}
+	if fn, ok := obj.(*types.Func); ok {
+		call.Key = u.getObjectKey(fn)
+		call.Func = fn
+		call.Dynamic = u.isInterfaceMethod(fn)
+	}
+	if closureKey, ok := c.lookupVar(obj); ok {
+		// Call through a variable holding a function literal
+		call.Key = closureKey
+		return call
+	}
+	if v, ok := obj.(*types.Var); ok && v.IsField() {
+		// Call through a function-typed struct field, qualified by the struct declaring it
+		if sel, ok := expr.(*ast.SelectorExpr); ok {
+			if owner := u.getFieldOwner(pkg.Info.Selections[sel]); owner != nil {
+				call.Key = u.getTypeKey(owner) + "." + v.Name()
+				call.Field = true
+				// Fields are never analyzed functions, so the package is kept even within the calling package
+				if ownerPkg := owner.Obj().Pkg(); ownerPkg != nil {
+					call.Package = ownerPkg.Name()
+					call.PackagePath = ownerPkg.Path()
+				}
+			}
+		}
+		return call
+	}
+	switch obj.(type) {
+	case *types.Func, *types.TypeName:
+		if obj.Pkg() != nil && obj.Pkg() != pkg.Types {
+			call.Package = obj.Pkg().Name()
+			call.PackagePath = obj.Pkg().Path()
+		}
+	}
+
+	return call
+}
+
+// resolveConversion describes a conversion to type t as a call target.
+// Named types keep their package so that the conversion can be classified like a call.
+func (u *goPureProjectGenerateUsecase) resolveConversion(t types.Type) callTarget {
+	call := callTarget{
+		Key: types.TypeString(t, func(p *types.Package) string {
+			return p.Name()
+		}),
+		Resolved:   true,
+		Conversion: true,
+	}
+	if named, ok := types.Unalias(t).(*types.Named); ok && named.Obj().Pkg() != nil {
+		call.Package = named.Obj().Pkg().Name()
+		call.PackagePath = named.Obj().Pkg().Path()
+	}
+	return call
+}
+
+// getFieldOwner returns the named struct type that declares the field of a selection,
+// following embedded fields the selection passes through; nil if it is not a field selection
+func (u *goPureProjectGenerateUsecase) getFieldOwner(selection *types.Selection) *types.Named {
+	if selection == nil || selection.Kind() != types.FieldVal {
+		return nil
+	}
+
+	owner := selection.Recv()
+	index := selection.Index()
+	for _, i := range index[:len(index)-1] {
+		st, ok := u.derefNamed(owner).Underlying().(*types.Struct)
+		if !ok {
+			return nil
+		}
+		owner = st.Field(i).Type()
+	}
+	named, ok := u.derefNamed(owner).(*types.Named)
+	if !ok {
+		return nil
+	}
+	return named
+}
+
+// derefNamed strips a pointer and aliases from a type
+func (u *goPureProjectGenerateUsecase) derefNamed(t types.Type) types.Type {
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	return types.Unalias(t)
+}
+
+// getTypeKey returns the key prefix of a named type, matching the receiver part of getObjectKey
+func (u *goPureProjectGenerateUsecase) getTypeKey(named *types.Named) string {
+	obj := named.Origin().Obj()
+	if obj.Pkg() == nil {
+		return obj.Name()
+	}
+	return obj.Pkg().Path() + "." + obj.Name()
+}
+
+// getCallName extracts the function name from a call expression as written in source.
+// Chains such as s.repo.Find or cmd.Flags().Get are kept whole so that an unresolved
+// call is never mistaken for an unrelated function with the same method name.
+// Elements of slices and maps have no name.
+func (u *goPureProjectGenerateUsecase) getCallName(expr ast.Expr, pkg *golang.GoPackage) string {
 	switch e := expr.(type) {
 	case *ast.Ident:
 		return e.Name
 	case *ast.SelectorExpr:
-		// For method calls like obj.Method()
-		if ident, ok := e.X.(*ast.Ident); ok {
-			return ident.Name + "." + e.Sel.Name
+		if base := u.getCallName(e.X, pkg); base != "" {
+			return base + "." + e.Sel.Name
 		}
 		return e.Sel.Name
+	case *ast.CallExpr:
+		if fun := u.getCallName(e.Fun, pkg); fun != "" {
+			return fun + "()"
+		}
+	case *ast.ParenExpr, *ast.IndexExpr, *ast.IndexListExpr:
+		if inner := u.unwrapInstantiation(e, pkg); inner != e {
+			return u.getCallName(inner, pkg)
+		}
+	case *ast.StarExpr:
+		// Method expressions on pointer receivers such as (*T).Method
+		if base := u.getCallName(e.X, pkg); base != "" {
+			return "(*" + base + ")"
+		}
 	}
 	return ""
 }
 
+// isInterfaceMethod reports whether a function object is an abstract interface method
+func (u *goPureProjectGenerateUsecase) isInterfaceMethod(fn *types.Func) bool {
+	recv := fn.Signature().Recv()
+	return recv != nil && types.IsInterface(recv.Type())
+}
+
+// expandDynamicCalls replaces each interface method call with calls to every concrete
+// implementation found in the analyzed packages (class hierarchy analysis).
+// Interface calls without any implementation are kept as they are.
+func (u *goPureProjectGenerateUsecase) expandDynamicCalls(calls []callTarget, packages []*golang.GoPackage) []callTarget {
+	var expanded []callTarget
+	callMap := make(map[string]int) // call kind + key -> index in expanded
+
+	for _, call := range calls {
+		targets := []callTarget{call}
+		if call.Dynamic && call.Func != nil {
+			if impls := u.findImplementations(call.Func, packages); len(impls) > 0 {
+				targets = targets[:0]
+				for _, impl := range impls {
+					// Each target gets its own sites, as merging appends to them
+					target := callTarget{
+						Key:      u.getObjectKey(impl),
+						Resolved: true,
+						Dynamic:  true,
+						Kind:     call.Kind,
+						Func:     impl,
+						Sites:    slices.Clone(call.Sites),
+					}
+					if impl.Pkg() != nil {
+						target.Package = impl.Pkg().Name()
+						target.PackagePath = impl.Pkg().Path()
+					}
+					targets = append(targets, target)
+				}
+			}
+		}
+
+		for _, target := range targets {
+			if i, ok := callMap[target.Kind+":"+target.Key]; ok {
+				expanded[i].Sites = append(expanded[i].Sites, target.Sites...)
+				continue
+			}
+			callMap[target.Kind+":"+target.Key] = len(expanded)
+			expanded = append(expanded, target)
+		}
+	}
+
+	return expanded
+}
+
+// findImplementations returns the concrete methods that implement an interface method
+// on named types declared in the analyzed packages
+func (u *goPureProjectGenerateUsecase) findImplementations(method *types.Func, packages []*golang.GoPackage) []*types.Func {
+	iface, ok := method.Signature().Recv().Type().Underlying().(*types.Interface)
+	if !ok {
+		return nil
+	}
+
+	var impls []*types.Func
+	seen := make(map[*types.Func]bool)
+	for _, pkg := range packages {
+		if pkg.Types == nil {
+			continue
+		}
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			typeName, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || typeName.IsAlias() {
+				continue
+			}
+			named, ok := typeName.Type().(*types.Named)
+			if !ok || named.TypeParams().Len() > 0 || types.IsInterface(named) {
+				continue
+			}
+
+			// Methods with pointer receivers are only in the method set of *T
+			var recvType types.Type = named
+			if !types.Implements(recvType, iface) {
+				recvType = types.NewPointer(named)
+				if !types.Implements(recvType, iface) {
+					continue
+				}
+			}
+
+			// Types embedding the implementing type share its method; methods promoted
+			// from an embedded interface are no more concrete than the call itself
+			obj, _, _ := types.LookupFieldOrMethod(recvType, false, method.Pkg(), method.Name())
+			if impl, ok := obj.(*types.Func); ok && !seen[impl] && !u.isInterfaceMethod(impl) {
+				seen[impl] = true
+				impls = append(impls, impl)
+			}
+		}
+	}
+
+	return impls
+}
+
+// getObjectKey generates the same key as getFunctionKey for a type-checked function object
+func (u *goPureProjectGenerateUsecase) getObjectKey(fn *types.Func) string {
+	fn = fn.Origin()
+
+	var parts []string
+	if fn.Pkg() != nil {
+		parts = append(parts, fn.Pkg().Path())
+	}
+	if recv := fn.Signature().Recv(); recv != nil {
+		recvType := recv.Type()
+		if ptr, ok := recvType.(*types.Pointer); ok {
+			recvType = ptr.Elem()
+		}
+		if named, ok := recvType.(*types.Named); ok {
+			parts = append(parts, named.Obj().Name())
+		}
+	}
+	parts = append(parts, fn.Name())
+
+	return strings.Join(parts, ".")
+}
+
+// functionIndex looks up analyzed functions by key
+type functionIndex struct {
+	functions map[string]model.Function
+	variants  map[string][]model.Function // key without build constraint -> platform-specific definitions
+}
+
+// newFunctionIndex indexes functions by key and platform-specific functions by their unconstrained key
+func (u *goPureProjectGenerateUsecase) newFunctionIndex(functions []model.Function) *functionIndex {
+	index := &functionIndex{
+		functions: make(map[string]model.Function),
+		variants:  make(map[string][]model.Function),
+	}
+	for _, fn := range functions {
+		index.functions[u.getFunctionKey(fn)] = fn
+		if fn.Constraint != "" {
+			unconstrained := fn
+			unconstrained.Constraint = ""
+			baseKey := u.getFunctionKey(unconstrained)
+			index.variants[baseKey] = append(index.variants[baseKey], fn)
+		}
+	}
+	return index
+}
+
+// lookupFunctions finds the analyzed functions a call refers to; a call matches several
+// functions only when they are platform-specific definitions of the same function
+func (u *goPureProjectGenerateUsecase) lookupFunctions(index *functionIndex, call callTarget) []model.Function {
+	// Try exact match first
+	if fn, exists := index.functions[call.Key]; exists {
+		return []model.Function{fn}
+	}
+	if variants, exists := index.variants[call.Key]; exists {
+		return variants
+	}
+
+	// Calls resolved by the type checker are never guessed
+	if call.Resolved {
+		return nil
+	}
+
+	// Try partial match (simple function name)
+	for key, fn := range index.functions {
+		if strings.HasSuffix(key, "/"+call.Key) || strings.HasSuffix(key, "."+call.Key) || fn.Name == call.Key {
+			return []model.Function{fn}
+		}
+	}
+	return nil
+}
+
+// getFileConstraint returns the build constraint recorded on functions of a file;
+// constraints are only recorded when all platforms are merged into one tree
+func (u *goPureProjectGenerateUsecase) getFileConstraint(req request.GenerateRequest, file *ast.File, filePath string) string {
+	if !req.AllPlatforms {
+		return ""
+	}
+	return u.repo.ExtractBuildConstraint(file, filePath)
+}
+
 // getFunctionKey generates a unique key for a function
+// Platform-specific definitions are told apart by their build constraint
 func (u *goPureProjectGenerateUsecase) getFunctionKey(fn model.Function) string {
+	if fn.Constraint != "" {
+		unconstrained := fn
+		unconstrained.Constraint = ""
+		return fmt.Sprintf("%s@%s", u.getFunctionKey(unconstrained), u.getConstraintKey(fn.Constraint))
+	}
+	// Full import paths keep same-named packages of different modules apart
+	pkg := fn.PackagePath
+	if pkg == "" {
+		pkg = fn.Package
+	}
 	if fn.Receiver != "" {
-		return fmt.Sprintf("%s.%s.%s", fn.Package, fn.Receiver, fn.Name)
+		return fmt.Sprintf("%s.%s.%s", pkg, fn.Receiver, fn.Name)
 	}
-	return fmt.Sprintf("%s.%s", fn.Package, fn.Name)
+	return fmt.Sprintf("%s.%s", pkg, fn.Name)
+}
+
+// getConstraintKey returns a build constraint in the form used in function keys, without
+// spaces, e.g. (linux||darwin)&&amd64
+func (u *goPureProjectGenerateUsecase) getConstraintKey(constraint string) string {
+	return strings.ReplaceAll(constraint, " ", "")
 }
 
 // buildFunctionSignature builds a full function signature like "func name(args) returnTypes"
@@ -226,6 +1285,18 @@ func (u *goPureProjectGenerateUsecase) buildFunctionSignature(fn model.Function)
 	}
 
 	sig.WriteString(fn.Name)
+
+	// Add type parameters for generic functions
+	if len(fn.TypeParams) > 0 {
+		var typeParams []string
+		for _, tp := range fn.TypeParams {
+			typeParams = append(typeParams, fmt.Sprintf("%s %s", tp.Name, tp.Type))
+		}
+		sig.WriteString("[")
+		sig.WriteString(strings.Join(typeParams, ", "))
+		sig.WriteString("]")
+	}
+
 	sig.WriteString("(")
 
 	// Add parameters
@@ -263,7 +1334,7 @@ func (u *goPureProjectGenerateUsecase) getRelativePath(absPath string) string {
 	// Get current working directory
 	cwd, err := filepath.Abs(".")
 	if err != nil {
-		fmt.Printf("Warning: failed to get current directory: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Warning: failed to get current directory: %v\n", err)
 		return absPath
 	}
 
@@ -275,28 +1346,72 @@ func (u *goPureProjectGenerateUsecase) getRelativePath(absPath string) string {
 	return absPath
 }
 
-// buildHierarchicalCallTree builds a hierarchical call tree structure from entry points
-func (u *goPureProjectGenerateUsecase) buildHierarchicalCallTree(entryPoints []model.Function, allFunctions []model.Function, functionCalls map[string][]string, importMap map[string]string) []model.CallTreeNode {
-	// Create function map for quick lookup
-	funcMap := make(map[string]model.Function)
-	for _, fn := range allFunctions {
-		funcMap[u.getFunctionKey(fn)] = fn
+// callTreeSource holds the analysis results call trees are built from
+type callTreeSource struct {
+	index       *functionIndex
+	calls       map[string][]callTarget      // function key -> called functions
+	fileImports map[string]map[string]string // file -> package name -> full import path
+	modules     *golang.ModuleInfo           // nil when no go.mod was found
+	followDeps  int                          // dependency package levels to descend into
+}
+
+// Origins of call tree nodes
+const (
+	originInternal   = "internal"    // analyzed source or the main module(s)
+	originStdlib     = "stdlib"      // Go standard library
+	originThirdParty = "third_party" // dependency module
+	originBuiltin    = "builtin"     // predeclared function such as len or append
+)
+
+// classifyPackagePath tells where a package comes from and, for dependencies,
+// which module and version provide it
+func (u *goPureProjectGenerateUsecase) classifyPackagePath(packagePath string, modules *golang.ModuleInfo) (origin, module, version string) {
+	if packagePath == "" {
+		return "", "", ""
 	}
 
+	if modules != nil {
+		if mainModule := modules.MainModule(packagePath); mainModule != nil {
+			return originInternal, mainModule.Path, ""
+		}
+	}
+
+	// Packages outside any module are named after the source root rather than a domain
+	firstElem, _, _ := strings.Cut(packagePath, "/")
+	if !strings.Contains(firstElem, ".") {
+		if u.repo.IsStandardPackage(packagePath) {
+			return originStdlib, "", ""
+		}
+		return originInternal, "", ""
+	}
+
+	if modules != nil {
+		module, version = modules.DependencyModule(packagePath)
+		// Modules replaced by a local directory are part of the workspace
+		if replacement, ok := modules.Replaces[module]; ok && replacement.Dir != "" {
+			return originInternal, module, ""
+		}
+	}
+	return originThirdParty, module, version
+}
+
+// buildHierarchicalCallTree builds a hierarchical call tree structure from entry points
+func (u *goPureProjectGenerateUsecase) buildHierarchicalCallTree(entryPoints []model.Function, source *callTreeSource) []model.CallTreeNode {
 	var callTreeNodes []model.CallTreeNode
 
 	// Build tree for each entry point
 	for _, ep := range entryPoints {
 		visited := make(map[string]bool)
-		node := u.buildTreeNodeRecursive(ep, funcMap, functionCalls, importMap, visited, 0, 10) // max depth 10
+		node := u.buildTreeNodeRecursive(ep, source, visited, 0, 10, 0) // max depth 10
 		callTreeNodes = append(callTreeNodes, node)
 	}
 
 	return callTreeNodes
 }
 
-// buildTreeNodeRecursive recursively builds a call tree node
-func (u *goPureProjectGenerateUsecase) buildTreeNodeRecursive(fn model.Function, funcMap map[string]model.Function, functionCalls map[string][]string, importMap map[string]string, visited map[string]bool, depth int, maxDepth int) model.CallTreeNode {
+// buildTreeNodeRecursive recursively builds a call tree node.
+// depDepth is the dependency package level of fn, as returned by getDependencyDepth.
+func (u *goPureProjectGenerateUsecase) buildTreeNodeRecursive(fn model.Function, source *callTreeSource, visited map[string]bool, depth int, maxDepth int, depDepth int) model.CallTreeNode {
 	funcKey := u.getFunctionKey(fn)
 
 	// Build full function signature for title
@@ -309,12 +1424,29 @@ func (u *goPureProjectGenerateUsecase) buildTreeNodeRecursive(fn model.Function,
 		Title:       fullSignature,
 		Name:        fn.Name,
 		Package:     fn.Package,
+		PackagePath: fn.PackagePath,
 		File:        relativePath,
 		Line:        fn.Line,
 		Kind:        fn.Kind,
 		Receiver:    fn.Receiver,
+		Constraint:  fn.Constraint,
+		TypeParams:  fn.TypeParams,
 		Parameters:  fn.Parameters,
 		ReturnTypes: fn.ReturnTypes,
+		Origin:      originInternal,
+	}
+	if u.isDependencyFunction(fn, source) {
+		node.Origin = originThirdParty
+		node.ModuleVersion = fn.ModuleVersion
+	}
+	node.Module = fn.Module
+
+	// Panicking is a property of the function, so recursive and truncated nodes are marked too
+	for _, call := range source.calls[funcKey] {
+		if call.Builtin && call.Key == "panic" {
+			node.Panics = true
+			break
+		}
 	}
 
 	// Check for circular reference
@@ -333,60 +1465,109 @@ func (u *goPureProjectGenerateUsecase) buildTreeNodeRecursive(fn model.Function,
 	defer func() { visited[funcKey] = false }()
 
 	// Get called functions
-	calls, ok := functionCalls[funcKey]
+	calls, ok := source.calls[funcKey]
 	if !ok || len(calls) == 0 {
 		return node
 	}
 
 	// Build child nodes
-	for _, calledFuncName := range calls {
-		// Try to find the function in funcMap
-		var childFn model.Function
-		found := false
-
-		// Try exact match first
-		if fn, exists := funcMap[calledFuncName]; exists {
-			childFn = fn
-			found = true
-		} else {
-			// Try partial match (simple function name)
-			for key, fn := range funcMap {
-				if strings.HasSuffix(key, "."+calledFuncName) || fn.Name == calledFuncName {
-					childFn = fn
-					found = true
-					break
-				}
+	for _, call := range calls {
+		// Platform-specific definitions of the same function each get a child
+		childFns := u.lookupFunctions(source.index, call)
+		childDepDepth := 0
+		if len(childFns) > 0 {
+			childDepDepth = u.getDependencyDepth(fn, childFns[0], depDepth, source)
+		}
+		if len(childFns) > 0 && childDepDepth <= source.followDeps {
+			for _, childFn := range childFns {
+				childNode := u.buildTreeNodeRecursive(childFn, source, visited, depth+1, maxDepth, childDepDepth)
+				childNode.ModuleBoundary = childDepDepth > 0 && childFn.Module != fn.Module
+				childNode.IsDynamic = call.Dynamic
+				childNode.IsField = call.Field
+				childNode.Via = call.Via
+				childNode.CallKind = call.Kind
+				childNode.CallSites = call.Sites
+				node.Children = append(node.Children, childNode)
 			}
+			continue
 		}
 
-		if found {
-			childNode := u.buildTreeNodeRecursive(childFn, funcMap, functionCalls, importMap, visited, depth+1, maxDepth)
-			node.Children = append(node.Children, childNode)
-		} else {
-			// Create a placeholder node for external or unresolved functions
-			// Extract package name and lookup in importMap
-			packageName := u.extractPackageFromFunctionName(calledFuncName)
-			packagePath := ""
+		// Create a placeholder node for external or unresolved functions
+		packageName := call.Package
+		packagePath := call.PackagePath
+		if len(childFns) > 0 {
+			// Dependency function beyond the requested depth
+			packageName = childFns[0].Package
+			packagePath = childFns[0].PackagePath
+		} else if !call.Resolved {
+			// Extract package name and lookup in the imports of the calling file
+			packageName = u.extractPackageFromFunctionName(call.Key)
 			if packageName != "" {
-				if path, ok := importMap[packageName]; ok {
+				if path, ok := source.fileImports[fn.File][packageName]; ok {
 					packagePath = path
 				}
 			}
+		}
 
-			node.Children = append(node.Children, model.CallTreeNode{
-				Title:       calledFuncName + "()",
-				Name:        calledFuncName,
-				Package:     packageName,
-				PackagePath: packagePath,
-				Kind:        "external",
-				File:        "",
-			})
+		// Keys carry the full import path; titles use the package name as written in code
+		title := call.Key
+		if packagePath != "" && packageName != "" && strings.HasPrefix(call.Key, packagePath+".") {
+			title = packageName + call.Key[len(packagePath):]
 		}
+
+		childNode := model.CallTreeNode{
+			Title:       title + "()",
+			Name:        call.Key,
+			Package:     packageName,
+			PackagePath: packagePath,
+			Kind:        "external",
+			File:        "",
+			IsDynamic:   call.Dynamic,
+			IsField:     call.Field,
+			Via:         call.Via,
+			CallKind:    call.Kind,
+			CallSites:   call.Sites,
+		}
+		switch {
+		case call.Builtin:
+			childNode.Kind = "builtin"
+			childNode.Origin = originBuiltin
+		case call.Conversion && packagePath == "":
+			// Conversion to a predeclared or composite type
+			childNode.Kind = "conversion"
+			childNode.Origin = originBuiltin
+		case call.Conversion:
+			childNode.Kind = "conversion"
+			childNode.Origin, childNode.Module, childNode.ModuleVersion = u.classifyPackagePath(packagePath, source.modules)
+		default:
+			childNode.Origin, childNode.Module, childNode.ModuleVersion = u.classifyPackagePath(packagePath, source.modules)
+		}
+		node.Children = append(node.Children, childNode)
 	}
 
 	return node
 }
 
+// getDependencyDepth returns the dependency level of fn when called from caller at callerDepth.
+// Levels count packages the way followDependencies loads them: a dependency package called
+// from the analyzed source is level 1, and every further package entered adds one.
+// Functions outside dependencies are level 0.
+func (u *goPureProjectGenerateUsecase) getDependencyDepth(caller, fn model.Function, callerDepth int, source *callTreeSource) int {
+	if !u.isDependencyFunction(fn, source) {
+		return 0
+	}
+	if callerDepth > 0 && caller.PackagePath == fn.PackagePath {
+		return callerDepth
+	}
+	return callerDepth + 1
+}
+
+// isDependencyFunction reports whether fn was followed into the source of a dependency module
+func (u *goPureProjectGenerateUsecase) isDependencyFunction(fn model.Function, source *callTreeSource) bool {
+	origin, _, _ := u.classifyPackagePath(fn.PackagePath, source.modules)
+	return origin == originThirdParty
+}
+
 // buildCallTreeVisualization creates a text visualization of the call tree
 func (u *goPureProjectGenerateUsecase) buildCallTreeVisualization(nodes []model.CallTreeNode) string {
 	var result strings.Builder
@@ -422,11 +1603,48 @@ func (u *goPureProjectGenerateUsecase) visualizeNodeRecursive(result *strings.Bu
 	if node.File != "" {
 		result.WriteString(fmt.Sprintf(" (%s:%d)", node.File, node.Line))
 	}
+	if len(node.CallSites) > 0 {
+		result.WriteString(fmt.Sprintf(" @%s:%d", node.CallSites[0].File, node.CallSites[0].Line))
+		if len(node.CallSites) > 1 {
+			result.WriteString(fmt.Sprintf(" x%d", len(node.CallSites)))
+		}
+	}
+	if node.ModuleBoundary {
+		result.WriteString(fmt.Sprintf(" [module: %s@%s]", node.Module, node.ModuleVersion))
+	}
+	if node.Constraint != "" {
+		result.WriteString(fmt.Sprintf(" [build: %s]", node.Constraint))
+	}
 	if node.IsRecursive {
 		result.WriteString(" [recursive]")
 	}
-	if node.Kind == "external" {
-		result.WriteString(" [external]")
+	if node.IsDynamic {
+		result.WriteString(" [dynamic]")
+	}
+	if node.IsField {
+		result.WriteString(" [field]")
+	}
+	if node.Via != "" {
+		result.WriteString(fmt.Sprintf(" [via %s]", node.Via))
+	}
+	if node.CallKind != "" && node.CallKind != callKindCall {
+		result.WriteString(fmt.Sprintf(" [%s]", node.CallKind))
+	}
+	if node.Panics {
+		result.WriteString(" [panics]")
+	}
+	switch node.Kind {
+	case "builtin", "conversion":
+		result.WriteString(fmt.Sprintf(" [%s]", node.Kind))
+	case "external":
+		switch node.Origin {
+		case "":
+			result.WriteString(" [external]")
+		case originThirdParty:
+			result.WriteString(" [third-party]")
+		default:
+			result.WriteString(fmt.Sprintf(" [%s]", node.Origin))
+		}
 	}
 	result.WriteString("\n")
 
@@ -0,0 +1,765 @@
+package golang
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/ryo-arima/ctree/pkg/entity/model"
+	"github.com/ryo-arima/ctree/pkg/entity/request"
+	"github.com/ryo-arima/ctree/pkg/repository/golang"
+)
+
+// analyzeFixture analyzes the module in testdata/name the way the generate command does
+func analyzeFixture(t *testing.T, name string, req request.GenerateRequest) *model.CTree {
+	t.Helper()
+	req.SourcePath = filepath.Join("testdata", name)
+	req.Recursive = true
+	req.MaxDepth = 10
+
+	ctree, err := NewGoPureProjectGenerateUsecase(nil).Analyze(req)
+	if err != nil {
+		t.Fatalf("Analyze(%s) failed: %v", name, err)
+	}
+	return ctree
+}
+
+// findEdge returns the call graph edge from one function key to another
+func findEdge(ctree *model.CTree, from, to string) (model.CallEdge, bool) {
+	for _, edge := range ctree.CallGraph {
+		if edge.From == from && edge.To == to {
+			return edge, true
+		}
+	}
+	return model.CallEdge{}, false
+}
+
+// callsTo returns the callee keys recorded on the function with the given key
+func callsTo(ctree *model.CTree, key string) []string {
+	u := &goPureProjectGenerateUsecase{}
+	for _, fn := range ctree.Functions {
+		if u.getFunctionKey(fn) == key {
+			return fn.CallsTo
+		}
+	}
+	return nil
+}
+
+// findFunction returns the first analyzed function with the given name
+func findFunction(ctree *model.CTree, name string) (model.Function, bool) {
+	for _, fn := range ctree.Functions {
+		if fn.Name == name {
+			return fn, true
+		}
+	}
+	return model.Function{}, false
+}
+
+// hasString reports whether list contains s
+func hasString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAnalyzeResolvesCalls(t *testing.T) {
+	ctree := analyzeFixture(t, "calls", request.GenerateRequest{})
+
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want bool
+	}{
+		{"method call", "example.com/calls.main", "example.com/calls.server.run", true},
+		{"package function", "example.com/calls.main", "example.com/calls.helper", true},
+		{"imported function", "example.com/calls.main", "example.com/calls/store.New", true},
+		{"method through a field", "example.com/calls.server.run", "example.com/calls/store.Store.Save", true},
+		{"unexported method", "example.com/calls/store.Store.Save", "example.com/calls/store.Store.log", true},
+		{"same name in another package", "example.com/calls.server.run", "example.com/calls/other.Save", false},
+		{"same name in another package, unexported", "example.com/calls/store.Store.Save", "example.com/calls/other.log", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := findEdge(ctree, tt.from, tt.to); ok != tt.want {
+				t.Errorf("edge %s -> %s: got %v, want %v", tt.from, tt.to, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeExpandsInterfaceCalls(t *testing.T) {
+	const caller = "example.com/dispatch.total"
+	tests := []struct {
+		dispatch string
+		want     []string
+	}{
+		{"static", nil},
+		{"cha", []string{"example.com/dispatch.Circle.Area", "example.com/dispatch.Square.Area"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dispatch, func(t *testing.T) {
+			ctree := analyzeFixture(t, "dispatch", request.GenerateRequest{Dispatch: tt.dispatch})
+
+			var got []string
+			for _, edge := range ctree.CallGraph {
+				if edge.From != caller {
+					continue
+				}
+				if !edge.Dynamic {
+					t.Errorf("edge %s -> %s is not marked dynamic", edge.From, edge.To)
+				}
+				got = append(got, edge.To)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("callees of %s: got %v, want %v", caller, got, tt.want)
+			}
+
+			// The method Wrapped promotes from its embedded interface is not an implementation
+			calls := callsTo(ctree, caller)
+			for _, callee := range calls {
+				if tt.dispatch == "cha" && callee == "example.com/dispatch.Shape.Area" {
+					t.Errorf("interface method kept in %v", calls)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeCollectsGlobalClosures(t *testing.T) {
+	ctree := analyzeFixture(t, "closures", request.GenerateRequest{})
+
+	tests := []struct {
+		name string
+		from string
+		to   string
+	}{
+		{"variable holding a literal", "example.com/closures.glob..func1", "example.com/closures.setup"},
+		{"literal in a composite literal", "example.com/closures.glob..func2", "example.com/closures.run"},
+		{"literal passed as an argument", "example.com/closures.glob..func3", "example.com/closures.setup"},
+		{"call through the variable", "example.com/closures.main", "example.com/closures.glob..func1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := findEdge(ctree, tt.from, tt.to); !ok {
+				t.Errorf("missing edge %s -> %s", tt.from, tt.to)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRecordsCallKinds(t *testing.T) {
+	ctree := analyzeFixture(t, "kinds", request.GenerateRequest{})
+	const caller = "example.com/kinds.main"
+
+	tests := []struct {
+		callee string
+		want   string
+	}{
+		{"example.com/kinds.worker", "go"},
+		{"example.com/kinds.cleanup", "defer"},
+		{"example.com/kinds.handler", "ref"},
+		{"example.com/kinds.register", "call"},
+		{"example.com/kinds.direct", "call"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.callee, func(t *testing.T) {
+			edge, ok := findEdge(ctree, caller, tt.callee)
+			if !ok {
+				t.Fatalf("missing edge %s -> %s", caller, tt.callee)
+			}
+			if edge.CallKind != tt.want {
+				t.Errorf("call kind %q, want %q", edge.CallKind, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeResolvesGenericInstantiations(t *testing.T) {
+	ctree := analyzeFixture(t, "generics", request.GenerateRequest{})
+	const caller = "example.com/generics.main"
+
+	tests := []struct {
+		name   string
+		callee string
+		want   bool
+	}{
+		{"explicit instantiation with several type arguments", "example.com/generics.Map", true},
+		{"explicit instantiation with one type argument", "example.com/generics.Identity", true},
+		{"function value in a slice literal", "example.com/generics.first", true},
+		{"slice element", "fns", false},
+		{"map element", "m", false},
+	}
+	calls := callsTo(ctree, caller)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasString(calls, tt.callee); got != tt.want {
+				t.Errorf("%s in callees %v: got %v, want %v", tt.callee, calls, got, tt.want)
+			}
+		})
+	}
+	if _, ok := findEdge(ctree, caller, "example.com/generics.Map"); !ok {
+		t.Errorf("missing edge %s -> example.com/generics.Map", caller)
+	}
+}
+
+func TestAnalyzeExtractsTypeParameters(t *testing.T) {
+	ctree := analyzeFixture(t, "generics", request.GenerateRequest{})
+
+	tests := []struct {
+		function string
+		want     []model.Parameter
+	}{
+		{"Map", []model.Parameter{{Name: "K", Type: "comparable"}, {Name: "V", Type: "any"}}},
+		{"Identity", []model.Parameter{{Name: "T", Type: "any"}}},
+		{"length", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			fn, ok := findFunction(ctree, tt.function)
+			if !ok {
+				t.Fatalf("%s not found", tt.function)
+			}
+			if !reflect.DeepEqual(fn.TypeParams, tt.want) {
+				t.Errorf("type parameters %v, want %v", fn.TypeParams, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRecordsCallSites(t *testing.T) {
+	ctree := analyzeFixture(t, "sites", request.GenerateRequest{})
+	const caller = "example.com/sites.main"
+	file := filepath.Join("testdata", "sites", "main.go")
+
+	tests := []struct {
+		callee string
+		count  int
+		sites  []model.CallSite
+	}{
+		{"example.com/sites.helper", 2, []model.CallSite{{File: file, Line: 8, Column: 8}, {File: file, Line: 11, Column: 9}}},
+		{"example.com/sites.once", 1, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.callee, func(t *testing.T) {
+			edge, ok := findEdge(ctree, caller, tt.callee)
+			if !ok {
+				t.Fatalf("missing edge %s -> %s", caller, tt.callee)
+			}
+			if edge.Count != tt.count || !reflect.DeepEqual(edge.Sites, tt.sites) {
+				t.Errorf("count %d, sites %v; want count %d, sites %v", edge.Count, edge.Sites, tt.count, tt.sites)
+			}
+		})
+	}
+}
+
+func TestAnalyzeKeepsCallSitesOfExpandedCalls(t *testing.T) {
+	ctree := analyzeFixture(t, "sites", request.GenerateRequest{Dispatch: "cha"})
+	const caller = "example.com/sites.measure"
+	file := filepath.Join("testdata", "sites", "main.go")
+	dynamic := []model.CallSite{{File: file, Line: 26, Column: 8}, {File: file, Line: 27, Column: 8}, {File: file, Line: 28, Column: 8}}
+
+	// Every implementation gets the sites of the interface call and of its own direct call
+	tests := []struct {
+		callee string
+		sites  []model.CallSite
+	}{
+		{"example.com/sites.Circle.Area", slices.Concat(dynamic, []model.CallSite{{File: file, Line: 29, Column: 8}})},
+		{"example.com/sites.Square.Area", slices.Concat(dynamic, []model.CallSite{{File: file, Line: 30, Column: 8}})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.callee, func(t *testing.T) {
+			edge, ok := findEdge(ctree, caller, tt.callee)
+			if !ok {
+				t.Fatalf("missing edge %s -> %s", caller, tt.callee)
+			}
+			if !reflect.DeepEqual(edge.Sites, tt.sites) {
+				t.Errorf("sites %v, want %v", edge.Sites, tt.sites)
+			}
+		})
+	}
+}
+
+// findTreeNodes returns every call tree node with the given name, at any depth
+func findTreeNodes(nodes []model.CallTreeNode, name string) []model.CallTreeNode {
+	var found []model.CallTreeNode
+	for _, node := range nodes {
+		if node.Name == name {
+			found = append(found, node)
+		}
+		found = append(found, findTreeNodes(node.Children, name)...)
+	}
+	return found
+}
+
+func TestAnalyzeScopesImportAliasesToTheirFile(t *testing.T) {
+	ctree := analyzeFixture(t, "aliases", request.GenerateRequest{})
+
+	tests := []struct {
+		caller string
+		want   string
+	}{
+		{"core", "example.org/api/core/v1"},
+		{"apps", "example.org/api/apps/v1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caller, func(t *testing.T) {
+			file := filepath.Join("testdata", "aliases", tt.caller+".go")
+			if got := ctree.FileImports[file]["v1"]; got != tt.want {
+				t.Errorf("v1 imported as %q in %s, want %q", got, file, tt.want)
+			}
+
+			nodes := findTreeNodes(ctree.CallTree, tt.caller)
+			if len(nodes) != 1 || len(nodes[0].Children) != 1 {
+				t.Fatalf("got %d %s nodes, want 1 with the call to v1.Get", len(nodes), tt.caller)
+			}
+			if got := nodes[0].Children[0].PackagePath; got != tt.want {
+				t.Errorf("v1.Get package path %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeSelectsFilesByBuildConstraints(t *testing.T) {
+	tests := []struct {
+		goos   string
+		goarch string
+		want   map[string]string // function name -> declaring file
+	}{
+		{"linux", "amd64", map[string]string{"main": "main.go", "open": "open_linux.go", "tune": "tune.go"}},
+		{"windows", "amd64", map[string]string{"main": "main.go", "open": "open_windows.go"}},
+		{"linux", "arm64", map[string]string{"main": "main.go", "open": "open_linux.go", "tune": "tune_other.go"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.goos+"/"+tt.goarch, func(t *testing.T) {
+			ctree := analyzeFixture(t, "platforms", request.GenerateRequest{GOOS: tt.goos, GOARCH: tt.goarch})
+
+			got := make(map[string]string)
+			for _, fn := range ctree.Functions {
+				got[fn.Name] = filepath.Base(fn.File)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("functions: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeKeysPlatformVariants(t *testing.T) {
+	ctree := analyzeFixture(t, "platforms", request.GenerateRequest{AllPlatforms: true})
+	const caller = "example.com/platforms.main"
+
+	for _, callee := range []string{
+		"example.com/platforms.open@linux",
+		"example.com/platforms.open@windows",
+		"example.com/platforms.tune@(linux||darwin)&&amd64",
+		"example.com/platforms.tune@!amd64",
+	} {
+		if _, ok := findEdge(ctree, caller, callee); !ok {
+			t.Errorf("missing edge %s -> %s", caller, callee)
+		}
+	}
+}
+
+func TestAnalyzeFindsTestEntryPoints(t *testing.T) {
+	tests := []struct {
+		name         string
+		includeTests bool
+		want         map[string]string // entry point name -> kind
+	}{
+		{"without tests", false, map[string]string{"main": "entrypoint"}},
+		{"with tests", true, map[string]string{
+			"main":           "entrypoint",
+			"TestMain":       "testmain",
+			"TestHello":      "test",
+			"TestAliased":    "test",
+			"BenchmarkHello": "benchmark",
+			"FuzzHello":      "fuzz",
+			"ExampleHello":   "example",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctree := analyzeFixture(t, "tests", request.GenerateRequest{IncludeTests: tt.includeTests})
+
+			got := make(map[string]string)
+			for _, ep := range ctree.EntryPoints {
+				got[ep.Name] = ep.Kind
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("entry points: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeSelectsRequestedEntryPoints(t *testing.T) {
+	tests := []struct {
+		name string
+		req  request.GenerateRequest
+		want map[string]string // entry point name -> kind
+	}{
+		{"directive only", request.GenerateRequest{}, map[string]string{"job": "entry"}},
+		{"exported as entry", request.GenerateRequest{ExportedAsEntry: true}, map[string]string{"job": "entry", "Serve": "exported", "Open": "exported"}},
+		{"pattern by package name", request.GenerateRequest{EntryPatterns: []string{"lib.Server.*"}}, map[string]string{"job": "entry", "Serve": "entry", "stop": "entry"}},
+		{"pattern by import path", request.GenerateRequest{EntryPatterns: []string{"example.com/entries.helper"}}, map[string]string{"job": "entry", "helper": "entry"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctree := analyzeFixture(t, "entries", tt.req)
+
+			got := make(map[string]string)
+			for _, ep := range ctree.EntryPoints {
+				got[ep.Name] = ep.Kind
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("entry points: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchEntryPattern(t *testing.T) {
+	method := model.Function{
+		Name:        "Generate",
+		Receiver:    "goPureProjectGenerateUsecase",
+		Package:     "golang",
+		PackagePath: "github.com/ryo-arima/ctree/pkg/usecase/golang",
+		File:        "pkg/usecase/golang/pure_project.go",
+	}
+	tests := []struct {
+		name    string
+		pattern string
+		want    bool
+	}{
+		{"import path", "github.com/ryo-arima/ctree/pkg/usecase/golang.*.Generate", true},
+		{"relative directory", "pkg/usecase/golang.*.Generate", true},
+		{"package name", "golang.goPureProjectGenerateUsecase.Generate", true},
+		{"wildcard name", "golang.*.Gen*", true},
+		{"other package", "pkg/usecase/python.*.Generate", false},
+		{"function instead of method", "golang.Generate", false},
+	}
+
+	u := &goPureProjectGenerateUsecase{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := u.matchEntryPattern(method, []string{tt.pattern}); got != tt.want {
+				t.Errorf("matchEntryPattern(%q) = %v, want %v", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeClassifiesCalleeOrigins(t *testing.T) {
+	ctree := analyzeFixture(t, "origins", request.GenerateRequest{})
+
+	tests := []struct {
+		name    string
+		origin  string
+		module  string
+		version string
+	}{
+		{"fmt.Println", "stdlib", "", ""},
+		{"Save", "internal", "example.com/origins", ""},
+		{"lib.Run", "third_party", "github.com/acme/lib", "v1.2.3"},
+		{"println", "builtin", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes := findTreeNodes(ctree.CallTree, tt.name)
+			if len(nodes) != 1 {
+				t.Fatalf("got %d nodes, want 1", len(nodes))
+			}
+			node := nodes[0]
+			if node.Origin != tt.origin || node.Module != tt.module || node.ModuleVersion != tt.version {
+				t.Errorf("origin %q, module %q@%q; want %q, %q@%q", node.Origin, node.Module, node.ModuleVersion, tt.origin, tt.module, tt.version)
+			}
+		})
+	}
+}
+
+func TestClassifyPackagePath(t *testing.T) {
+	modules := &golang.ModuleInfo{
+		MainModules: []golang.GoModule{{Path: "example.com/app"}},
+		Versions: map[string]string{
+			"github.com/spf13/cobra": "v1.8.0",
+			"example.org/lib":        "v1.0.0",
+			"example.org/lib/v2":     "v2.1.0",
+		},
+	}
+
+	tests := []struct {
+		packagePath string
+		origin      string
+		module      string
+		version     string
+	}{
+		{"", "", "", ""},
+		{"example.com/app", "internal", "example.com/app", ""},
+		{"example.com/app/store", "internal", "example.com/app", ""},
+		{"fmt", "stdlib", "", ""},
+		{"net/http", "stdlib", "", ""},
+		{"github.com/spf13/cobra", "third_party", "github.com/spf13/cobra", "v1.8.0"},
+		{"github.com/spf13/cobra/doc", "third_party", "github.com/spf13/cobra", "v1.8.0"},
+		{"example.org/lib/v2/client", "third_party", "example.org/lib/v2", "v2.1.0"},
+		{"example.net/unknown", "third_party", "", ""},
+		{"proj/util", "internal", "", ""},
+	}
+
+	u := &goPureProjectGenerateUsecase{repo: golang.NewGoPureProjectRepository()}
+	for _, tt := range tests {
+		t.Run(tt.packagePath, func(t *testing.T) {
+			origin, module, version := u.classifyPackagePath(tt.packagePath, modules)
+			if origin != tt.origin || module != tt.module || version != tt.version {
+				t.Errorf("got %q, %q@%q; want %q, %q@%q", origin, module, version, tt.origin, tt.module, tt.version)
+			}
+		})
+	}
+}
+
+func TestAnalyzeFollowsDependencies(t *testing.T) {
+	// The vendored dependency is only importable from within the fixture module
+	t.Setenv("GOFLAGS", "-mod=vendor")
+	t.Chdir(filepath.Join("testdata", "deps"))
+
+	tests := []struct {
+		followDeps int
+		analyzed   []string // dependency functions expanded in the call tree
+		external   []string // dependency functions shown as placeholders
+	}{
+		{0, nil, []string{"example.org/lib.Run"}},
+		{1, []string{"Run", "step"}, []string{"example.org/lib/inner.Work"}},
+		{2, []string{"Run", "step", "Work", "helper"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("follow-deps=%d", tt.followDeps), func(t *testing.T) {
+			req := request.GenerateRequest{SourcePath: ".", Recursive: true, MaxDepth: 10, FollowDeps: tt.followDeps}
+			ctree, err := NewGoPureProjectGenerateUsecase(nil).Analyze(req)
+			if err != nil {
+				t.Fatalf("Analyze failed: %v", err)
+			}
+
+			for _, name := range tt.analyzed {
+				nodes := findTreeNodes(ctree.CallTree, name)
+				if len(nodes) == 0 || nodes[0].File == "" {
+					t.Errorf("%s is not expanded in the call tree", name)
+				}
+			}
+			for _, name := range tt.external {
+				if nodes := findTreeNodes(ctree.CallTree, name); len(nodes) == 0 || nodes[0].Kind != "external" {
+					t.Errorf("%s is not shown as an external call", name)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeOutsideModule(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "proj")
+	files := map[string]string{
+		"main.go":      "package main\n\nimport \"proj/util\"\n\nfunc main() { util.Do() }\n",
+		"util/util.go": "package util\n\nfunc Do() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req := request.GenerateRequest{SourcePath: root, Recursive: true, MaxDepth: 10}
+	ctree, err := NewGoPureProjectGenerateUsecase(nil).Analyze(req)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	// Import paths start at the source root, so local imports resolve
+	if _, ok := findEdge(ctree, "proj.main", "proj/util.Do"); !ok {
+		t.Errorf("missing edge proj.main -> proj/util.Do in %v", ctree.CallGraph)
+	}
+	nodes := findTreeNodes(ctree.CallTree, "Do")
+	if len(nodes) != 1 || nodes[0].Origin != "internal" {
+		t.Errorf("got nodes %+v, want one internal Do", nodes)
+	}
+}
+
+func TestAnalyzeKeysFunctionsByImportPath(t *testing.T) {
+	ctree := analyzeFixture(t, "workspace", request.GenerateRequest{})
+	const caller = "example.com/app.main"
+
+	// Packages named util in both modules are kept apart
+	for _, callee := range []string{"example.com/app/util.Do", "example.com/lib/util.Do"} {
+		if _, ok := findEdge(ctree, caller, callee); !ok {
+			t.Errorf("missing edge %s -> %s", caller, callee)
+		}
+	}
+
+	got := make(map[string]int)
+	for _, module := range ctree.Modules {
+		got[module.Path] = module.Functions
+	}
+	want := map[string]int{"example.com/app": 2, "example.com/lib": 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("functions per module: got %v, want %v", got, want)
+	}
+}
+
+func TestAnalyzeClassifiesBuiltinsAndConversions(t *testing.T) {
+	ctree := analyzeFixture(t, "builtins", request.GenerateRequest{})
+
+	tests := []struct {
+		name   string
+		kind   string
+		panics bool
+	}{
+		{"make", "builtin", false},
+		{"append", "builtin", false},
+		{"len", "builtin", false},
+		{"main.celsius", "conversion", false},
+		{"check", "function", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes := findTreeNodes(ctree.CallTree, tt.name)
+			if len(nodes) != 1 {
+				t.Fatalf("got %d nodes, want 1", len(nodes))
+			}
+			if nodes[0].Kind != tt.kind || nodes[0].Panics != tt.panics {
+				t.Errorf("kind %q, panics %v; want %q, %v", nodes[0].Kind, nodes[0].Panics, tt.kind, tt.panics)
+			}
+		})
+	}
+}
+
+func TestAnalyzeMarksPanickingRecursiveCalls(t *testing.T) {
+	ctree := analyzeFixture(t, "panics", request.GenerateRequest{})
+
+	nodes := findTreeNodes(ctree.CallTree, "walk")
+	if len(nodes) != 2 {
+		t.Fatalf("got %d walk nodes, want the call and its recursive call", len(nodes))
+	}
+	for _, node := range nodes {
+		if !node.Panics {
+			t.Errorf("walk node (recursive: %v) is not marked as panicking", node.IsRecursive)
+		}
+	}
+}
+
+func TestAnalyzeResolvesSelectorChains(t *testing.T) {
+	ctree := analyzeFixture(t, "selectors", request.GenerateRequest{})
+	const caller = "example.com/selectors.main"
+
+	tests := []struct {
+		name   string
+		callee string
+		count  int
+	}{
+		{"chained selector and method expression", "example.com/selectors.repo.Close", 2},
+		{"method on a call result", "example.com/selectors.repo.Find", 2},
+		{"method returning the receiver field", "example.com/selectors.service.Repo", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edge, ok := findEdge(ctree, caller, tt.callee)
+			if !ok {
+				t.Fatalf("missing edge %s -> %s", caller, tt.callee)
+			}
+			if edge.Count != tt.count {
+				t.Errorf("%d call sites, want %d", edge.Count, tt.count)
+			}
+		})
+	}
+
+	// Function-typed fields are qualified by the struct declaring them
+	if calls := callsTo(ctree, caller); !hasString(calls, "example.com/selectors.service.onClose") {
+		t.Errorf("field call missing from callees %v", calls)
+	}
+}
+
+func TestAnalyzeMarksFieldAndInterfaceCalls(t *testing.T) {
+	ctree := analyzeFixture(t, "fields", request.GenerateRequest{})
+
+	tests := []struct {
+		callee  string
+		field   bool
+		dynamic bool
+	}{
+		{"example.com/fields.server.handler", true, false},
+		{"example.com/fields.Shape.Area", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.callee, func(t *testing.T) {
+			nodes := findTreeNodes(ctree.CallTree, tt.callee)
+			if len(nodes) != 1 {
+				t.Fatalf("got %d nodes, want 1", len(nodes))
+			}
+			if nodes[0].IsField != tt.field || nodes[0].IsDynamic != tt.dynamic {
+				t.Errorf("field %v, dynamic %v; want field %v, dynamic %v", nodes[0].IsField, nodes[0].IsDynamic, tt.field, tt.dynamic)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRecordsEmbeddingPaths(t *testing.T) {
+	ctree := analyzeFixture(t, "embedding", request.GenerateRequest{})
+
+	tests := []struct {
+		name string
+		from string
+		to   string
+		via  string
+	}{
+		{"promoted through two fields", "example.com/embedding.fromService", "example.com/embedding.Logger.Log", "Base.Logger"},
+		{"promoted through one field", "example.com/embedding.fromBase", "example.com/embedding.Logger.Log", "Logger"},
+		{"declared method", "example.com/embedding.main", "example.com/embedding.Service.Run", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edge, ok := findEdge(ctree, tt.from, tt.to)
+			if !ok {
+				t.Fatalf("missing edge %s -> %s", tt.from, tt.to)
+			}
+			if edge.Via != tt.via {
+				t.Errorf("via %q, want %q", edge.Via, tt.via)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRecordsTreeNodePackagePaths(t *testing.T) {
+	ctree := analyzeFixture(t, "calls", request.GenerateRequest{})
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"main", "example.com/calls"},
+		{"run", "example.com/calls"},
+		{"Save", "example.com/calls/store"},
+		{"log", "example.com/calls/store"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes := findTreeNodes(ctree.CallTree, tt.name)
+			if len(nodes) == 0 {
+				t.Fatalf("%s is not in the call tree", tt.name)
+			}
+			for _, node := range nodes {
+				if node.PackagePath != tt.want {
+					t.Errorf("package path %q, want %q", node.PackagePath, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,7 @@
+package main
+
+import v1 "example.org/api/apps/v1"
+
+func apps() {
+	v1.Get()
+}
@@ -0,0 +1,7 @@
+package main
+
+import v1 "example.org/api/core/v1"
+
+func core() {
+	v1.Get()
+}
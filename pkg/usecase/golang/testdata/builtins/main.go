@@ -0,0 +1,16 @@
+package main
+
+type celsius float64
+
+func check(n int) {
+	if n < 0 {
+		panic("negative")
+	}
+}
+
+func main() {
+	s := make([]int, 0)
+	s = append(s, 1)
+	_ = celsius(1.5)
+	check(len(s))
+}
@@ -0,0 +1,19 @@
+package main
+
+import "example.com/calls/store"
+
+type server struct {
+	store *store.Store
+}
+
+func (s *server) run() {
+	s.store.Save("key")
+}
+
+func helper() {}
+
+func main() {
+	s := &server{store: store.New()}
+	s.run()
+	helper()
+}
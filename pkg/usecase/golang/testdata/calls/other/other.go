@@ -0,0 +1,6 @@
+package other
+
+// Save has the name of a method called in main and must never be linked to it
+func Save(string) {}
+
+func log(string) {}
@@ -0,0 +1,13 @@
+package store
+
+type Store struct{}
+
+func New() *Store {
+	return &Store{}
+}
+
+func (s *Store) Save(key string) {
+	s.log(key)
+}
+
+func (s *Store) log(string) {}
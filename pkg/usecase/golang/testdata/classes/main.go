@@ -0,0 +1,31 @@
+package main
+
+import "io"
+
+// Base carries the shared state
+type Base struct {
+	ID int `json:"id"`
+}
+
+func (b Base) Describe() string { return "" }
+
+func (b *Base) Reset() {}
+
+type Service struct {
+	*Base
+	io.Writer
+	Name, Owner string `json:"name"`
+}
+
+func (s *Service) Run() error { return nil }
+
+type Runner interface {
+	io.Closer
+	Run() error
+}
+
+type Count int
+
+type Alias = Service
+
+func main() {}
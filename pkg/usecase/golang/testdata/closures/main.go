@@ -0,0 +1,35 @@
+package main
+
+type Command struct {
+	Use string
+	Run func(args []string)
+}
+
+func run(args []string) {}
+
+func setup() {}
+
+func configure(f func()) int {
+	f()
+	return 0
+}
+
+var handler = func() {
+	setup()
+}
+
+var rootCmd = &Command{
+	Use: "app",
+	Run: func(args []string) {
+		run(args)
+	},
+}
+
+var configured = configure(func() {
+	setup()
+})
+
+func main() {
+	handler()
+	rootCmd.Run(nil)
+}
@@ -0,0 +1,5 @@
+module example.com/deps
+
+go 1.22
+
+require example.org/lib v1.0.0
@@ -0,0 +1,36 @@
+package main
+
+type Shape interface {
+	Area() float64
+}
+
+type Circle struct{ r float64 }
+
+func (c Circle) Area() float64 { return 3 * c.r * c.r }
+
+type Square struct{ side float64 }
+
+func (s *Square) Area() float64 { return s.side * s.side }
+
+// Labeled shares the method of the Circle it embeds
+type Labeled struct {
+	Circle
+	label string
+}
+
+// Wrapped only forwards to the interface it embeds
+type Wrapped struct {
+	Shape
+}
+
+func total(shapes []Shape) float64 {
+	var sum float64
+	for _, s := range shapes {
+		sum += s.Area()
+	}
+	return sum
+}
+
+func main() {
+	total([]Shape{Circle{1}, &Square{2}, Labeled{}, Wrapped{}})
+}
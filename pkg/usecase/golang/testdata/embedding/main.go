@@ -0,0 +1,30 @@
+package main
+
+type Logger struct{}
+
+func (l *Logger) Log() {}
+
+type Base struct {
+	*Logger
+}
+
+type Service struct {
+	Base
+}
+
+func (s *Service) Run() {}
+
+func fromService(s *Service) {
+	s.Log()
+}
+
+func fromBase(s *Service) {
+	s.Base.Log()
+}
+
+func main() {
+	s := &Service{Base{&Logger{}}}
+	fromService(s)
+	fromBase(s)
+	s.Run()
+}
@@ -0,0 +1,20 @@
+package lib
+
+type Server struct{}
+
+func (s *Server) Serve() {}
+
+func (s *Server) stop() {}
+
+type worker struct{}
+
+func (w *worker) Run() {}
+
+func Open() {}
+
+func helper() {}
+
+// job is run by a scheduler outside the analyzed source
+//
+//ctree:entry
+func job() {}
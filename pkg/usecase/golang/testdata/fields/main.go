@@ -0,0 +1,16 @@
+package main
+
+type Shape interface {
+	Area() float64
+}
+
+type server struct {
+	handler func()
+	shape   Shape
+}
+
+func main() {
+	s := server{handler: func() {}}
+	s.handler()
+	s.shape.Area()
+}
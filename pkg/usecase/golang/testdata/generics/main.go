@@ -0,0 +1,29 @@
+package main
+
+func Map[K comparable, V any](keys []K, f func(K) V) map[K]V {
+	out := make(map[K]V)
+	for _, k := range keys {
+		out[k] = f(k)
+	}
+	return out
+}
+
+func Identity[T any](v T) T { return v }
+
+func length(s string) int { return len(s) }
+
+func first() {}
+
+func second() {}
+
+func main() {
+	Map[string, int]([]string{"a"}, length)
+	Identity[int](1)
+
+	fns := []func(){first, second}
+	for i := range fns {
+		fns[i]()
+	}
+	m := map[string]func(){"first": first}
+	m["first"]()
+}
@@ -0,0 +1,40 @@
+package main
+
+type Reader interface {
+	Read() string
+}
+
+type Closer interface {
+	Close() error
+}
+
+type ReadCloser interface {
+	Reader
+	Closer
+}
+
+type Number interface {
+	~int | ~float64
+}
+
+type Any interface{}
+
+type File struct{}
+
+func (f File) Read() string { return "" }
+
+func (f *File) Close() error { return nil }
+
+type Buffered struct {
+	File
+}
+
+type Stream struct {
+	*File
+}
+
+type Box[T any] struct{ v T }
+
+func (b Box[T]) Read() string { return "" }
+
+func main() {}
@@ -0,0 +1,5 @@
+module example.com/imports
+
+go 1.22
+
+require github.com/acme/lib v1.2.3
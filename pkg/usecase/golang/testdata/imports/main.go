@@ -0,0 +1,17 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	str "strings"
+
+	. "example.com/imports/store"
+	"github.com/acme/lib"
+)
+
+func main() {
+	fmt.Println(str.ToUpper("hello"))
+	Save()
+	lib.Run()
+	run()
+}
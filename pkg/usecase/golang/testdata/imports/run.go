@@ -0,0 +1,12 @@
+package main
+
+import (
+	"fmt"
+
+	"example.com/imports/store"
+)
+
+func run() {
+	fmt.Println("run")
+	store.Save()
+}
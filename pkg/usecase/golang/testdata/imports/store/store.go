@@ -0,0 +1,7 @@
+package store
+
+import "strings"
+
+func Save() {
+	_ = strings.TrimSpace(" ")
+}
@@ -0,0 +1,18 @@
+package main
+
+func worker() {}
+
+func cleanup() {}
+
+func handler() {}
+
+func register(f func()) {}
+
+func direct() {}
+
+func main() {
+	go worker()
+	defer cleanup()
+	register(handler)
+	direct()
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"fmt"
+
+	"example.com/origins/store"
+	"github.com/acme/lib"
+)
+
+func main() {
+	fmt.Println("hello")
+	store.Save()
+	lib.Run()
+	println("done")
+}
@@ -0,0 +1,14 @@
+package main
+
+func walk(n int) {
+	if n < 0 {
+		panic("negative")
+	}
+	if n > 0 {
+		walk(n - 1)
+	}
+}
+
+func main() {
+	walk(3)
+}
@@ -0,0 +1,5 @@
+//go:build (linux || darwin) && amd64
+
+package main
+
+func tune() {}
@@ -0,0 +1,22 @@
+package main
+
+type repo struct{}
+
+func (r *repo) Find() *repo { return r }
+
+func (r *repo) Close() {}
+
+type service struct {
+	repo    *repo
+	onClose func()
+}
+
+func (s *service) Repo() *repo { return s.repo }
+
+func main() {
+	s := &service{repo: &repo{}, onClose: func() {}}
+	s.repo.Find().Close()
+	s.Repo().Find()
+	(*repo).Close(s.repo)
+	s.onClose()
+}
@@ -0,0 +1,31 @@
+package main
+
+func helper() {}
+
+func once() {}
+
+func main() {
+	helper()
+	once()
+	if true {
+		helper()
+	}
+}
+
+type Shape interface{ Area() int }
+
+type Circle struct{}
+
+func (Circle) Area() int { return 1 }
+
+type Square struct{}
+
+func (Square) Area() int { return 2 }
+
+func measure(s Shape, c Circle, q Square) {
+	s.Area()
+	s.Area()
+	s.Area()
+	c.Area()
+	q.Area()
+}
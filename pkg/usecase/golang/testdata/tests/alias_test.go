@@ -0,0 +1,5 @@
+package main
+
+import tst "testing"
+
+func TestAliased(t *tst.T) { hello() }
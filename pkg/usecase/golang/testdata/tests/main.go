@@ -0,0 +1,7 @@
+package main
+
+func hello() string { return "hello" }
+
+func main() {
+	println(hello())
+}
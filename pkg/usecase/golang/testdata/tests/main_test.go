@@ -0,0 +1,28 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) { os.Exit(m.Run()) }
+
+func TestHello(t *testing.T) { hello() }
+
+func BenchmarkHello(b *testing.B) { hello() }
+
+func FuzzHello(f *testing.F) { hello() }
+
+func ExampleHello() { hello() }
+
+// Not entry points: wrong names or signatures
+
+func Testing(t *testing.T) {}
+
+func TestWithBenchmark(b *testing.B) {}
+
+func TestWithResult(t *testing.T) error { return nil }
+
+func Examples() {}
+
+func ExampleWithParam(n int) {}
@@ -0,0 +1,33 @@
+package main
+
+var base = 10
+
+var derived = base * 2
+
+const Limit = 5
+
+const Double = Limit * 2
+
+type Level int
+
+// Levels of the logger
+const (
+	Debug Level = iota
+	Info
+)
+
+var (
+	counter int
+	total   int
+)
+
+func inc() {
+	counter++
+	total += base
+	total = 0
+}
+
+func main() {
+	inc()
+	_ = derived + Double
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"example.com/app/util"
+	libutil "example.com/lib/util"
+)
+
+func main() {
+	util.Do()
+	libutil.Do()
+}